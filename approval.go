@@ -0,0 +1,276 @@
+package notify
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultApprovalTimeout is the time waited for a decision
+const DefaultApprovalTimeout = 10 * time.Minute
+
+// action ids and callback data prefixes of approval buttons
+const (
+	approveAction = "notify_approve"
+	denyAction    = "notify_deny"
+)
+
+var (
+	// ErrApprovalTimeout is returned when nobody answered an approval request in time
+	ErrApprovalTimeout = errors.New("approval request timed out")
+	// ErrApprovalUnsupported is returned when no enabled provider supports approvals
+	ErrApprovalUnsupported = errors.New("no enabled provider supports approvals")
+)
+
+// ApprovalOptions of an approval request
+type ApprovalOptions struct {
+	// Timeout defaults to DefaultApprovalTimeout
+	Timeout      time.Duration
+	ApproveLabel string
+	DenyLabel    string
+}
+
+// Approval is the decision taken on an approval request
+type Approval struct {
+	Approved bool
+	// By is the username who decided
+	By       string
+	Provider string
+	At       time.Time
+}
+
+// approvals holds the pending requests waiting for a decision
+type approvals struct {
+	sync.Mutex
+	pending map[string]chan *Approval
+
+	stopListener context.CancelFunc
+
+	// telegramStarted is set once the listener runs, failed starts are
+	// retried by the next request
+	telegramMutex   sync.Mutex
+	telegramStarted bool
+}
+
+func newApprovals() *approvals {
+	return &approvals{pending: make(map[string]chan *Approval)}
+}
+
+// resolve delivers the decision to the waiting request, the first one wins
+func (a *approvals) resolve(id string, approval *Approval) bool {
+	a.Lock()
+	decision, ok := a.pending[id]
+	delete(a.pending, id)
+	a.Unlock()
+
+	if ok {
+		decision <- approval
+	}
+	return ok
+}
+
+// stop the telegram listener if it was started
+func (a *approvals) stop() {
+	a.Lock()
+	stopListener := a.stopListener
+	a.Unlock()
+
+	if stopListener != nil {
+		stopListener()
+	}
+}
+
+// RequestApproval sends the message with approve and deny actions to slack
+// and telegram and blocks until someone decides, the timeout expires or the
+// context is canceled. Slack buttons require SlackApprovalHandler to be
+// registered as the interactivity request url of the slack app.
+func (n *Notify) RequestApproval(ctx context.Context, message string, opts *ApprovalOptions) (*Approval, error) {
+	if opts == nil {
+		opts = &ApprovalOptions{}
+	}
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultApprovalTimeout
+	}
+	approveLabel, denyLabel := opts.ApproveLabel, opts.DenyLabel
+	if approveLabel == "" {
+		approveLabel = "Approve"
+	}
+	if denyLabel == "" {
+		denyLabel = "Deny"
+	}
+
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	id := hex.EncodeToString(buf)
+	decision := make(chan *Approval, 1)
+	n.approvals.Lock()
+	n.approvals.pending[id] = decision
+	n.approvals.Unlock()
+	defer func() {
+		n.approvals.Lock()
+		delete(n.approvals.pending, id)
+		n.approvals.Unlock()
+	}()
+
+	var sent int
+	var err error
+	if n.options.Slack {
+		if err = n.sendSlackApproval(ctx, id, message, approveLabel, denyLabel); err == nil {
+			sent++
+		}
+	}
+	if n.options.Telegram {
+		if err = n.sendTelegramApproval(ctx, id, message, approveLabel, denyLabel); err == nil {
+			sent++
+		}
+	}
+	if sent == 0 {
+		if err == nil {
+			err = ErrApprovalUnsupported
+		}
+		return nil, err
+	}
+
+	select {
+	case approval := <-decision:
+		return approval, nil
+	case <-time.After(timeout):
+		return nil, ErrApprovalTimeout
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (n *Notify) sendSlackApproval(ctx context.Context, id, message, approveLabel, denyLabel string) error {
+	return n.slackClient.sendHTTPRequest(ctx, &SlackMessage{
+		Text:     message,
+		Username: n.slackClient.UserName,
+		IconURL:  n.slackClient.IconURL,
+		Channel:  n.slackClient.Channel,
+		Blocks: []SlackBlock{
+			SlackSection(message),
+			SlackActions(
+				SlackButton(approveLabel, approveAction, id, SlackButtonPrimary),
+				SlackButton(denyLabel, denyAction, id, SlackButtonDanger),
+			),
+		},
+	})
+}
+
+// SlackApprovalHandler returns the interactivity handler resolving approvals requested on slack
+func (n *Notify) SlackApprovalHandler(signingSecret string) http.Handler {
+	return &SlackHandler{
+		SigningSecret: signingSecret,
+		OnInteraction: func(interaction *SlackInteraction) *SlackMessage {
+			for _, action := range interaction.Actions {
+				if action.ActionID != approveAction && action.ActionID != denyAction {
+					continue
+				}
+				by := interaction.User.Username
+				if by == "" {
+					by = interaction.User.Name
+				}
+				approval := &Approval{Approved: action.ActionID == approveAction, By: by, Provider: ProviderSlack, At: time.Now()}
+				if n.approvals.resolve(action.Value, approval) && interaction.ResponseURL != "" {
+					n.replaceSlackApproval(interaction.ResponseURL, interaction.Message.Text, approval)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// replaceSlackApproval replaces the buttons of the original message with the decision
+func (n *Notify) replaceSlackApproval(responseURL, text string, approval *Approval) {
+	decision := "Denied"
+	if approval.Approved {
+		decision = "Approved"
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"replace_original": true,
+		"text":             text + "\n" + decision + " by " + approval.By,
+	})
+	if err != nil {
+		return
+	}
+	//nolint:errcheck // best effort update
+	n.slackClient.post(context.Background(), responseURL, body)
+}
+
+func (n *Notify) sendTelegramApproval(ctx context.Context, id, message, approveLabel, denyLabel string) error {
+	if err := n.startTelegramApprovals(ctx); err != nil {
+		return err
+	}
+	chatID, err := n.telegramClient.ResolveChatIDCtx(ctx, n.telegramClient.chatID)
+	if err != nil {
+		return err
+	}
+	keyboard, err := json.Marshal(map[string]interface{}{
+		"inline_keyboard": [][]map[string]string{{
+			{"text": approveLabel, "callback_data": approveAction + ":" + id},
+			{"text": denyLabel, "callback_data": denyAction + ":" + id},
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	return n.telegramClient.callAPI(ctx, "sendMessage", url.Values{
+		"chat_id":      {chatID},
+		"text":         {message},
+		"reply_markup": {string(keyboard)},
+	}, nil)
+}
+
+// startTelegramApprovals polls the bot updates in background to receive decisions
+func (n *Notify) startTelegramApprovals(ctx context.Context) error {
+	n.approvals.telegramMutex.Lock()
+	defer n.approvals.telegramMutex.Unlock()
+
+	if n.approvals.telegramStarted {
+		return nil
+	}
+	listener := n.telegramClient.NewListener()
+	listener.HandleCallback(n.handleTelegramCallback)
+	// validate the chat before polling in background
+	if _, err := listener.allowedChats(ctx); err != nil {
+		return err
+	}
+	listenCtx, cancel := context.WithCancel(context.Background())
+	n.approvals.Lock()
+	n.approvals.stopListener = cancel
+	n.approvals.Unlock()
+	//nolint:errcheck // stopped on close
+	go listener.Listen(listenCtx)
+	n.approvals.telegramStarted = true
+	return nil
+}
+
+func (n *Notify) handleTelegramCallback(query *TelegramCallbackQuery) string {
+	parts := strings.SplitN(query.Data, ":", 2)
+	if len(parts) != 2 || (parts[0] != approveAction && parts[0] != denyAction) {
+		return ""
+	}
+	by := query.From.Username
+	if by == "" {
+		by = strconv.FormatInt(query.From.ID, 10)
+	}
+	approval := &Approval{Approved: parts[0] == approveAction, By: by, Provider: ProviderTelegram, At: time.Now()}
+	if !n.approvals.resolve(parts[1], approval) {
+		return "This request is no longer pending"
+	}
+	if approval.Approved {
+		return "Approved"
+	}
+	return "Denied"
+}
@@ -0,0 +1,262 @@
+package notify
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const awsTimeFormat = "20060102T150405Z"
+
+// AWSCredentials used to sign requests to aws services
+type AWSCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// awsSTSEndpoint exchanges web identity tokens for credentials
+const awsSTSEndpoint = "https://sts.amazonaws.com/"
+
+// awsCredentialsRefresh renews assumed credentials ahead of their expiration
+const awsCredentialsRefresh = 5 * time.Minute
+
+// awsWebIdentity caches the credentials assumed with the web identity token
+var awsWebIdentity struct {
+	sync.Mutex
+	credentials AWSCredentials
+	expiration  time.Time
+}
+
+// awsSTSClient is used for the unsigned web identity exchange
+var awsSTSClient = &http.Client{Timeout: 10 * time.Second}
+
+// resolve the credentials with the default chain when they are not set:
+// environment variables, shared credentials and config files of AWS_PROFILE,
+// then the web identity token of kubernetes service accounts (IRSA)
+func (c AWSCredentials) resolve() (AWSCredentials, error) {
+	if c.AccessKeyID != "" || c.SecretAccessKey != "" {
+		return c, nil
+	}
+	if accessKeyID := os.Getenv("AWS_ACCESS_KEY_ID"); accessKeyID != "" {
+		return AWSCredentials{
+			AccessKeyID:     accessKeyID,
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		}, nil
+	}
+	if shared, ok := sharedAWSCredentials(); ok {
+		return shared, nil
+	}
+	if os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE") != "" && os.Getenv("AWS_ROLE_ARN") != "" {
+		return webIdentityAWSCredentials()
+	}
+	return c, errors.New("no aws credentials found")
+}
+
+// sharedAWSCredentials reads the profile of the shared credentials and config files
+func sharedAWSCredentials() (AWSCredentials, bool) {
+	profile := os.Getenv("AWS_PROFILE")
+	if profile == "" {
+		profile = "default"
+	}
+	home, _ := os.UserHomeDir()
+	credentialsFile := os.Getenv("AWS_SHARED_CREDENTIALS_FILE")
+	if credentialsFile == "" {
+		credentialsFile = filepath.Join(home, ".aws", "credentials")
+	}
+	configFile := os.Getenv("AWS_CONFIG_FILE")
+	if configFile == "" {
+		configFile = filepath.Join(home, ".aws", "config")
+	}
+
+	// the config file prefixes the sections of non default profiles
+	configSection := "profile " + profile
+	if profile == "default" {
+		configSection = profile
+	}
+	for _, source := range []struct{ file, section string }{{credentialsFile, profile}, {configFile, configSection}} {
+		values := readINISection(source.file, source.section)
+		if values["aws_access_key_id"] != "" {
+			return AWSCredentials{
+				AccessKeyID:     values["aws_access_key_id"],
+				SecretAccessKey: values["aws_secret_access_key"],
+				SessionToken:    values["aws_session_token"],
+			}, true
+		}
+	}
+	return AWSCredentials{}, false
+}
+
+// readINISection returns the key values of the section, empty if missing
+func readINISection(file, section string) map[string]string {
+	values := make(map[string]string)
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return values
+	}
+	var current string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			current = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+		if current != section {
+			continue
+		}
+		if i := strings.Index(line, "="); i > 0 {
+			values[strings.TrimSpace(line[:i])] = strings.TrimSpace(line[i+1:])
+		}
+	}
+	return values
+}
+
+// webIdentityAWSCredentials assumes AWS_ROLE_ARN with the token of AWS_WEB_IDENTITY_TOKEN_FILE
+func webIdentityAWSCredentials() (AWSCredentials, error) {
+	awsWebIdentity.Lock()
+	defer awsWebIdentity.Unlock()
+
+	if time.Now().Add(awsCredentialsRefresh).Before(awsWebIdentity.expiration) {
+		return awsWebIdentity.credentials, nil
+	}
+
+	token, err := ioutil.ReadFile(os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE"))
+	if err != nil {
+		return AWSCredentials{}, err
+	}
+	sessionName := os.Getenv("AWS_ROLE_SESSION_NAME")
+	if sessionName == "" {
+		sessionName = "notify"
+	}
+	values := url.Values{
+		"Action":           {"AssumeRoleWithWebIdentity"},
+		"Version":          {"2011-06-15"},
+		"RoleArn":          {os.Getenv("AWS_ROLE_ARN")},
+		"RoleSessionName":  {sessionName},
+		"WebIdentityToken": {strings.TrimSpace(string(token))},
+	}
+	resp, err := awsSTSClient.PostForm(awsSTSEndpoint, values)
+	if err != nil {
+		return AWSCredentials{}, err
+	}
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return AWSCredentials{}, err
+	}
+	//nolint:errcheck // silent fail
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return AWSCredentials{}, newResponseError(resp, buf)
+	}
+	var assumed struct {
+		Credentials struct {
+			AccessKeyID     string    `xml:"AccessKeyId"`
+			SecretAccessKey string    `xml:"SecretAccessKey"`
+			SessionToken    string    `xml:"SessionToken"`
+			Expiration      time.Time `xml:"Expiration"`
+		} `xml:"AssumeRoleWithWebIdentityResult>Credentials"`
+	}
+	if err := xml.Unmarshal(buf, &assumed); err != nil {
+		return AWSCredentials{}, err
+	}
+	awsWebIdentity.credentials = AWSCredentials{
+		AccessKeyID:     assumed.Credentials.AccessKeyID,
+		SecretAccessKey: assumed.Credentials.SecretAccessKey,
+		SessionToken:    assumed.Credentials.SessionToken,
+	}
+	awsWebIdentity.expiration = assumed.Credentials.Expiration
+	return awsWebIdentity.credentials, nil
+}
+
+// signAWSRequest signs the request in place with aws signature version 4
+func signAWSRequest(req *http.Request, body []byte, service, region string, credentials AWSCredentials, now time.Time) {
+	amzDate := now.UTC().Format(awsTimeFormat)
+	date := amzDate[:8]
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if credentials.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", credentials.SessionToken)
+	}
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+
+	headers := map[string]string{"host": host}
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if strings.HasPrefix(name, "x-amz-") || name == "content-type" {
+			headers[name] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+credentials.SecretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+credentials.AccessKeyID+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+func canonicalQuery(values url.Values) string {
+	// aws expects spaces as %20 rather than +
+	return strings.ReplaceAll(values.Encode(), "+", "%20")
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,121 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// DefaultBarkTimeout to conclude operations
+const DefaultBarkTimeout = 5 * time.Second
+
+// DefaultBarkURL is the public bark server
+const DefaultBarkURL = "https://api.day.app"
+
+// Bark interruption levels
+const (
+	BarkLevelActive        = "active"
+	BarkLevelTimeSensitive = "timeSensitive"
+	BarkLevelPassive       = "passive"
+)
+
+// BarkClient pushes notifications to ios devices with bark
+type BarkClient struct {
+	client *retryablehttp.Client
+	// URL of the server, DefaultBarkURL if empty
+	URL       string
+	DeviceKey string
+	Title     string
+	Sound     string
+	// Group collects the notifications in the notification center
+	Group string
+	// Click is opened when the notification is tapped
+	Click   string
+	TimeOut time.Duration
+}
+
+// BarkMessage json structure
+type BarkMessage struct {
+	DeviceKey string `json:"device_key"`
+	Title     string `json:"title,omitempty"`
+	Body      string `json:"body"`
+	Sound     string `json:"sound,omitempty"`
+	Group     string `json:"group,omitempty"`
+	URL       string `json:"url,omitempty"`
+	Level     string `json:"level,omitempty"`
+}
+
+// SendInfo to bark
+func (bc *BarkClient) SendInfo(message string) error {
+	return bc.SendInfoCtx(context.Background(), message)
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (bc *BarkClient) SendInfoCtx(ctx context.Context, message string) error {
+	return bc.send(ctx, message, BarkLevelActive)
+}
+
+// SendWarning to bark
+func (bc *BarkClient) SendWarning(message string) error {
+	return bc.send(context.Background(), message, BarkLevelActive)
+}
+
+// SendError to bark breaking through focus modes
+func (bc *BarkClient) SendError(message string) error {
+	return bc.send(context.Background(), message, BarkLevelTimeSensitive)
+}
+
+func (bc *BarkClient) send(ctx context.Context, message, level string) error {
+	return bc.SendBarkNotificationCtx(ctx, &BarkMessage{
+		DeviceKey: bc.DeviceKey,
+		Title:     bc.Title,
+		Body:      message,
+		Sound:     bc.Sound,
+		Group:     bc.Group,
+		URL:       bc.Click,
+		Level:     level,
+	})
+}
+
+// SendBarkNotification with json structure
+func (bc *BarkClient) SendBarkNotification(barkMessage *BarkMessage) error {
+	return bc.SendBarkNotificationCtx(context.Background(), barkMessage)
+}
+
+// SendBarkNotificationCtx is SendBarkNotification canceled with the context
+func (bc *BarkClient) SendBarkNotificationCtx(ctx context.Context, barkMessage *BarkMessage) error {
+	body, err := json.Marshal(barkMessage)
+	if err != nil {
+		return err
+	}
+	server := bc.URL
+	if server == "" {
+		server = DefaultBarkURL
+	}
+	req, err := retryablehttp.NewRequest(http.MethodPost, strings.TrimSuffix(server, "/")+"/push", body)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/json; charset=utf-8")
+
+	resp, err := doTimeout(bc.client, req.WithContext(ctx), bc.TimeOut)
+	if err != nil {
+		return err
+	}
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck // silent fail
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newResponseError(resp, buf)
+	}
+	return nil
+}
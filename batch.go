@@ -0,0 +1,102 @@
+package notify
+
+import (
+	"sync"
+	"time"
+)
+
+// record is the archived form of a notification written by sinks
+type record struct {
+	Time     time.Time `json:"time"`
+	Severity string    `json:"severity,omitempty"`
+	Message  string    `json:"message"`
+}
+
+func newRecord(message string) record {
+	return record{Time: time.Now().UTC(), Message: message}
+}
+
+func newSeverityRecord(severity, message string) record {
+	r := newRecord(message)
+	r.Severity = severity
+	return r
+}
+
+// DefaultBatchFlushInterval is the period the pending batches of the sinks are written
+const DefaultBatchFlushInterval = 10 * time.Second
+
+// batchFlusher writes the pending batches of the sinks periodically
+type batchFlusher struct {
+	stop chan struct{}
+	done chan struct{}
+}
+
+// startBatchFlusher flushes the sinks every interval until the flusher is stopped
+func (n *Notify) startBatchFlusher(interval time.Duration) *batchFlusher {
+	f := &batchFlusher{stop: make(chan struct{}), done: make(chan struct{})}
+	go func() {
+		defer close(f.done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				n.flush()
+			case <-f.stop:
+				return
+			}
+		}
+	}()
+	return f
+}
+
+// close stops the flusher and waits for an in-flight flush
+func (f *batchFlusher) close() {
+	close(f.stop)
+	<-f.done
+}
+
+// batching reports whether a sink accumulates messages in batches
+func (options *Options) batching() bool {
+	for _, size := range []int{options.S3BatchSize, options.ElasticsearchBatchSize, options.SplunkBatchSize, options.ClickHouseBatchSize} {
+		if size > 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// recordBatch accumulates records of batching sinks
+type recordBatch struct {
+	mutex   sync.Mutex
+	records []record
+}
+
+// add appends the record and returns the batch once it reaches size records
+func (b *recordBatch) add(r record, size int) []record {
+	if size <= 1 {
+		return []record{r}
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.records = append(b.records, r)
+	if len(b.records) < size {
+		return nil
+	}
+	records := b.records
+	b.records = nil
+	return records
+}
+
+// drain returns the pending records and resets the batch
+func (b *recordBatch) drain() []record {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	records := b.records
+	b.records = nil
+	return records
+}
@@ -0,0 +1,104 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// DefaultBitrix24Timeout to conclude operations
+const DefaultBitrix24Timeout = 5 * time.Second
+
+// Bitrix24Client posts messages to bitrix24 chats through an inbound webhook
+type Bitrix24Client struct {
+	client *retryablehttp.Client
+	// WebHookURL is the inbound webhook, eg. https://portal.bitrix24.com/rest/1/secret/
+	WebHookURL string
+	// DialogID is a user id or a chat in the chatXXX form
+	DialogID string
+	// BotID and ClientID post as a chat bot (imbot.message.add) instead of the webhook user
+	BotID    string
+	ClientID string
+	TimeOut  time.Duration
+}
+
+// Bitrix24Message json structure
+type Bitrix24Message struct {
+	BotID    string `json:"BOT_ID,omitempty"`
+	ClientID string `json:"CLIENT_ID,omitempty"`
+	DialogID string `json:"DIALOG_ID"`
+	Message  string `json:"MESSAGE"`
+}
+
+// Bitrix24Response structure
+type Bitrix24Response struct {
+	Result           json.RawMessage `json:"result,omitempty"`
+	Error            string          `json:"error,omitempty"`
+	ErrorDescription string          `json:"error_description,omitempty"`
+}
+
+// SendInfo to bitrix24
+func (bc *Bitrix24Client) SendInfo(message string) error {
+	return bc.SendInfoCtx(context.Background(), message)
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (bc *Bitrix24Client) SendInfoCtx(ctx context.Context, message string) error {
+	return bc.SendBitrix24NotificationCtx(ctx, &Bitrix24Message{
+		BotID:    bc.BotID,
+		ClientID: bc.ClientID,
+		DialogID: bc.DialogID,
+		Message:  message,
+	})
+}
+
+// SendBitrix24Notification with json structure
+func (bc *Bitrix24Client) SendBitrix24Notification(bitrixMessage *Bitrix24Message) error {
+	return bc.SendBitrix24NotificationCtx(context.Background(), bitrixMessage)
+}
+
+// SendBitrix24NotificationCtx is SendBitrix24Notification canceled with the context
+func (bc *Bitrix24Client) SendBitrix24NotificationCtx(ctx context.Context, bitrixMessage *Bitrix24Message) error {
+	method := "im.message.add.json"
+	if bitrixMessage.BotID != "" {
+		method = "imbot.message.add.json"
+	}
+	body, err := json.Marshal(bitrixMessage)
+	if err != nil {
+		return err
+	}
+	req, err := retryablehttp.NewRequest(http.MethodPost, strings.TrimSuffix(bc.WebHookURL, "/")+"/"+method, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/json")
+
+	resp, err := doTimeout(bc.client, req.WithContext(ctx), bc.TimeOut)
+	if err != nil {
+		return err
+	}
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck // silent fail
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return newResponseError(resp, buf)
+	}
+	var bitrixResponse Bitrix24Response
+	if err := json.Unmarshal(buf, &bitrixResponse); err != nil {
+		return err
+	}
+	if bitrixResponse.Error != "" {
+		return fmt.Errorf("bitrix24: %s: %s", bitrixResponse.Error, bitrixResponse.ErrorDescription)
+	}
+	return nil
+}
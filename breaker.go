@@ -0,0 +1,134 @@
+package notify
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultCircuitCooldown is the wait before a trial delivery to an open circuit
+const DefaultCircuitCooldown = time.Minute
+
+// ErrCircuitOpen is returned for providers skipped while their circuit is open
+var ErrCircuitOpen = errors.New("circuit open")
+
+// CircuitBreaker stops delivering to a provider after consecutive failures
+type CircuitBreaker struct {
+	// Failures is the number of consecutive failures opening the circuit, 0 disables it
+	Failures int
+	// Cooldown before a trial delivery closing the circuit on success
+	Cooldown time.Duration
+	// Fallback is the provider receiving the messages while the circuit is open
+	Fallback string
+}
+
+// CircuitState is the state of the circuit of a provider
+type CircuitState string
+
+// Circuit states
+const (
+	CircuitClosed   CircuitState = "closed"
+	CircuitOpen     CircuitState = "open"
+	CircuitHalfOpen CircuitState = "half-open"
+)
+
+// circuitBreaker returns the breaker of the provider, the global one if it has none
+func (options *Options) circuitBreaker(provider string) CircuitBreaker {
+	if breaker, ok := options.ProviderCircuitBreakers[provider]; ok {
+		return breaker
+	}
+	return options.CircuitBreaker
+}
+
+// circuit tracks the consecutive failures of a provider
+type circuit struct {
+	state    CircuitState
+	failures int
+	openedAt time.Time
+}
+
+// circuits holds the circuit of each provider
+type circuits struct {
+	sync.Mutex
+	providers map[string]*circuit
+}
+
+func newCircuits() *circuits {
+	return &circuits{providers: make(map[string]*circuit)}
+}
+
+// allow reports whether the provider can be delivered to, once the cooldown
+// of an open circuit expired a single trial delivery is allowed
+func (c *circuits) allow(provider string, breaker CircuitBreaker) bool {
+	if breaker.Failures <= 0 {
+		return true
+	}
+	c.Lock()
+	defer c.Unlock()
+
+	state, ok := c.providers[provider]
+	if !ok {
+		return true
+	}
+	switch state.state {
+	case CircuitOpen:
+		cooldown := breaker.Cooldown
+		if cooldown <= 0 {
+			cooldown = DefaultCircuitCooldown
+		}
+		if time.Since(state.openedAt) < cooldown {
+			return false
+		}
+		state.state = CircuitHalfOpen
+		return true
+	case CircuitHalfOpen:
+		// the trial delivery is in flight
+		return false
+	default:
+		return true
+	}
+}
+
+// record the outcome of a delivery and reports whether it opened the circuit
+func (c *circuits) record(provider string, breaker CircuitBreaker, success bool) bool {
+	if breaker.Failures <= 0 {
+		return false
+	}
+	c.Lock()
+	defer c.Unlock()
+
+	state, ok := c.providers[provider]
+	if !ok {
+		state = &circuit{state: CircuitClosed}
+		c.providers[provider] = state
+	}
+	if success {
+		state.state = CircuitClosed
+		state.failures = 0
+		return false
+	}
+	state.failures++
+	if state.state == CircuitHalfOpen || (state.state == CircuitClosed && state.failures >= breaker.Failures) {
+		state.state = CircuitOpen
+		state.openedAt = time.Now()
+		return true
+	}
+	return false
+}
+
+// states returns the state of the circuits by provider
+func (c *circuits) states() map[string]CircuitState {
+	c.Lock()
+	defer c.Unlock()
+
+	states := make(map[string]CircuitState, len(c.providers))
+	for provider, state := range c.providers {
+		states[provider] = state.state
+	}
+	return states
+}
+
+// CircuitStates returns the state of the circuit breakers by provider
+func (n *Notify) CircuitStates() map[string]CircuitState {
+	return n.circuits.states()
+}
@@ -0,0 +1,68 @@
+package notify
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCircuitTransitions(t *testing.T) {
+	breaker := CircuitBreaker{Failures: 2, Cooldown: time.Minute}
+	c := newCircuits()
+	expire := func() { c.providers["slack"].openedAt = time.Now().Add(-breaker.Cooldown) }
+
+	steps := []struct {
+		name    string
+		do      func() bool
+		want    bool
+		state   CircuitState
+		trigger func()
+	}{
+		{name: "first failure", do: func() bool { return c.record("slack", breaker, false) }, want: false, state: CircuitClosed},
+		{name: "threshold opens", do: func() bool { return c.record("slack", breaker, false) }, want: true, state: CircuitOpen},
+		{name: "open rejects", do: func() bool { return c.allow("slack", breaker) }, want: false, state: CircuitOpen},
+		{name: "cooldown allows a trial", trigger: expire, do: func() bool { return c.allow("slack", breaker) }, want: true, state: CircuitHalfOpen},
+		{name: "trial in flight rejects", do: func() bool { return c.allow("slack", breaker) }, want: false, state: CircuitHalfOpen},
+		{name: "failed trial reopens", do: func() bool { return c.record("slack", breaker, false) }, want: true, state: CircuitOpen},
+		{name: "reopened rejects", do: func() bool { return c.allow("slack", breaker) }, want: false, state: CircuitOpen},
+		{name: "second trial", trigger: expire, do: func() bool { return c.allow("slack", breaker) }, want: true, state: CircuitHalfOpen},
+		{name: "successful trial closes", do: func() bool { return c.record("slack", breaker, true) }, want: false, state: CircuitClosed},
+		{name: "closed allows", do: func() bool { return c.allow("slack", breaker) }, want: true, state: CircuitClosed},
+		{name: "failures were reset", do: func() bool { return c.record("slack", breaker, false) }, want: false, state: CircuitClosed},
+	}
+	for _, step := range steps {
+		if step.trigger != nil {
+			step.trigger()
+		}
+		if got := step.do(); got != step.want {
+			t.Errorf("%s: got %v, want %v", step.name, got, step.want)
+		}
+		if state := c.states()["slack"]; state != step.state {
+			t.Errorf("%s: state %s, want %s", step.name, state, step.state)
+		}
+	}
+}
+
+func TestCircuitDisabled(t *testing.T) {
+	c := newCircuits()
+	for i := 0; i < 10; i++ {
+		if c.record("slack", CircuitBreaker{}, false) {
+			t.Fatal("a disabled breaker opened the circuit")
+		}
+	}
+	if !c.allow("slack", CircuitBreaker{}) || len(c.states()) != 0 {
+		t.Error("a disabled breaker tracked the provider")
+	}
+}
+
+func TestCircuitBreakerOverride(t *testing.T) {
+	options := &Options{
+		CircuitBreaker:          CircuitBreaker{Failures: 5},
+		ProviderCircuitBreakers: map[string]CircuitBreaker{"slack": {Failures: 1, Fallback: "discord"}},
+	}
+	if breaker := options.circuitBreaker("slack"); breaker.Failures != 1 || breaker.Fallback != "discord" {
+		t.Errorf("circuitBreaker(slack) = %+v, want the provider breaker", breaker)
+	}
+	if breaker := options.circuitBreaker("discord"); breaker.Failures != 5 {
+		t.Errorf("circuitBreaker(discord) = %+v, want the global breaker", breaker)
+	}
+}
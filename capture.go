@@ -0,0 +1,221 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"html/template"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// maxCaptures is the number of requests kept by the capture mode
+const maxCaptures = 100
+
+// capturedResponse satisfies the response checks of every provider
+const capturedResponse = `{"ok":true,"errors":false,"code":0,"text":"Success"}`
+
+// Capture is a provider request intercepted in capture mode
+type Capture struct {
+	Provider string      `json:"provider"`
+	Time     time.Time   `json:"time"`
+	Method   string      `json:"method"`
+	URL      string      `json:"url"`
+	Header   http.Header `json:"header"`
+	Body     string      `json:"body"`
+}
+
+// captureStore keeps the latest captured requests
+type captureStore struct {
+	sync.RWMutex
+	captures []Capture
+}
+
+func (c *captureStore) add(capture *Capture) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.captures = append(c.captures, *capture)
+	if len(c.captures) > maxCaptures {
+		c.captures = c.captures[len(c.captures)-maxCaptures:]
+	}
+}
+
+func (c *captureStore) list() []Capture {
+	c.RLock()
+	defer c.RUnlock()
+
+	return append([]Capture(nil), c.captures...)
+}
+
+// redacted replaces the credentials of the captured requests
+const redacted = "REDACTED"
+
+var (
+	// credentialNames match the headers and query parameters holding credentials
+	credentialNames = regexp.MustCompile(`(?i)auth|key|token|secret|password|signature|cookie`)
+	// credentialPaths match the credentials in url paths, eg. telegram bot
+	// tokens and the secrets of slack and discord webhooks
+	credentialPaths = []*regexp.Regexp{
+		regexp.MustCompile(`(/bot)[0-9]+:[^/]+`),
+		regexp.MustCompile(`(/services/[^/]+/[^/]+/)[^/]+`),
+		regexp.MustCompile(`(/api/webhooks/[0-9]+/)[^/]+`),
+	}
+)
+
+// redactHeader returns a copy of the header without credentials
+func redactHeader(header http.Header) http.Header {
+	header = header.Clone()
+	for name := range header {
+		if credentialNames.MatchString(name) {
+			header[name] = []string{redacted}
+		}
+	}
+	return header
+}
+
+// redactURL returns the url without credentials
+func redactURL(u *url.URL) string {
+	redactedURL := *u
+	if _, ok := u.User.Password(); ok {
+		redactedURL.User = url.UserPassword(u.User.Username(), redacted)
+	}
+	for _, path := range credentialPaths {
+		redactedURL.Path = path.ReplaceAllString(redactedURL.Path, "${1}"+redacted)
+	}
+	redactedURL.RawPath = ""
+	if query := u.Query(); len(query) > 0 {
+		for name := range query {
+			if credentialNames.MatchString(name) {
+				query.Set(name, redacted)
+			}
+		}
+		redactedURL.RawQuery = query.Encode()
+	}
+	return redactedURL.String()
+}
+
+// captureTransport records provider requests instead of delivering them.
+// If forward is set the request is sent there for inspection with next.
+type captureTransport struct {
+	provider string
+	forward  string
+	store    *captureStore
+	next     http.RoundTripper
+}
+
+func (t *captureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		//nolint:errcheck // silent fail
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	capture := &Capture{
+		Provider: t.provider,
+		Time:     time.Now(),
+		Method:   req.Method,
+		URL:      redactURL(req.URL),
+		Header:   redactHeader(req.Header),
+		Body:     string(body),
+	}
+	t.store.add(capture)
+
+	if t.forward != "" {
+		forwardReq, err := http.NewRequestWithContext(req.Context(), req.Method, t.forward, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		forwardReq.Header = capture.Header.Clone()
+		forwardReq.Header.Set("X-Notify-Provider", t.provider)
+		forwardReq.Header.Set("X-Notify-Original-URL", capture.URL)
+		next := t.next
+		if next == nil {
+			next = http.DefaultTransport
+		}
+		return next.RoundTrip(forwardReq)
+	}
+
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          ioutil.NopCloser(bytes.NewBufferString(capturedResponse)),
+		ContentLength: int64(len(capturedResponse)),
+		Request:       req,
+	}, nil
+}
+
+// captureFunc records the payload of a provider not speaking http, which
+// skips dialing its server when it's set
+type captureFunc func(target string, payload []byte)
+
+// rawCapture returns the capture of a non http provider in capture mode, nil otherwise
+func (n *Notify) rawCapture(provider, method string) captureFunc {
+	if n.options == nil || !n.options.Capture {
+		return nil
+	}
+	return func(target string, payload []byte) {
+		n.captures.add(&Capture{
+			Provider: provider,
+			Time:     time.Now(),
+			Method:   method,
+			URL:      target,
+			Body:     string(payload),
+		})
+	}
+}
+
+// Captures returns the provider requests intercepted in capture mode, oldest first
+func (n *Notify) Captures() []Capture {
+	return n.captures.list()
+}
+
+var captureTemplate = template.Must(template.New("captures").Parse(`<!DOCTYPE html>
+<html>
+<head><title>notify captures</title>
+<style>body{font-family:monospace}pre{background:#f4f4f4;padding:8px;white-space:pre-wrap}</style>
+</head>
+<body>
+<h1>notify captures</h1>
+{{range .}}
+<h3>{{.Time.Format "15:04:05.000"}} {{.Provider}} {{.Method}} {{.URL}}</h3>
+<pre>{{range $name, $values := .Header}}{{$name}}: {{range $values}}{{.}} {{end}}
+{{end}}</pre>
+<pre>{{.Body}}</pre>
+{{else}}
+<p>no captured requests</p>
+{{end}}
+</body>
+</html>`))
+
+// CaptureHandler serves a web page with the captured requests, newest first.
+// Json is returned when the format query parameter is json.
+func (n *Notify) CaptureHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		captures := n.Captures()
+		for i, j := 0, len(captures)-1; i < j; i, j = i+1, j-1 {
+			captures[i], captures[j] = captures[j], captures[i]
+		}
+
+		if r.URL.Query().Get("format") == "json" {
+			w.Header().Set("Content-Type", "application/json")
+			//nolint:errcheck // client went away
+			json.NewEncoder(w).Encode(captures)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		//nolint:errcheck // client went away
+		captureTemplate.Execute(w, captures)
+	})
+}
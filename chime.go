@@ -0,0 +1,87 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// DefaultChimeTimeout to conclude operations
+const DefaultChimeTimeout = 5 * time.Second
+
+// Chime room mentions
+const (
+	ChimeMentionAll     = "@All"
+	ChimeMentionPresent = "@Present"
+)
+
+// ChimeClient handling amazon chime webhooks
+type ChimeClient struct {
+	client     *retryablehttp.Client
+	WebHookURL string
+	// Markdown renders the content as markdown
+	Markdown bool
+	// Mention prepends ChimeMentionAll or ChimeMentionPresent to messages
+	Mention string
+	TimeOut time.Duration
+}
+
+// ChimeMessage json structure
+type ChimeMessage struct {
+	Content string `json:"Content"`
+}
+
+// SendInfo to chime
+func (cc *ChimeClient) SendInfo(message string) error {
+	return cc.SendInfoCtx(context.Background(), message)
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (cc *ChimeClient) SendInfoCtx(ctx context.Context, message string) error {
+	if cc.Mention != "" {
+		message = cc.Mention + " " + message
+	}
+	if cc.Markdown {
+		// chime expects the /md prefix for markdown content
+		message = "/md " + message
+	}
+	return cc.SendChimeNotificationCtx(ctx, &ChimeMessage{Content: message})
+}
+
+// SendChimeNotification with json structure
+func (cc *ChimeClient) SendChimeNotification(chimeMessage *ChimeMessage) error {
+	return cc.SendChimeNotificationCtx(context.Background(), chimeMessage)
+}
+
+// SendChimeNotificationCtx is SendChimeNotification canceled with the context
+func (cc *ChimeClient) SendChimeNotificationCtx(ctx context.Context, chimeMessage *ChimeMessage) error {
+	body, err := json.Marshal(chimeMessage)
+	if err != nil {
+		return err
+	}
+	req, err := retryablehttp.NewRequest(http.MethodPost, cc.WebHookURL, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/json")
+
+	resp, err := doTimeout(cc.client, req.WithContext(ctx), cc.TimeOut)
+	if err != nil {
+		return err
+	}
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck // silent fail
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newResponseError(resp, buf)
+	}
+	return nil
+}
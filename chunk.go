@@ -0,0 +1,71 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Message length limits of the providers, longer messages are split in
+// numbered chunks
+const (
+	SlackMaxMessageLength   = 40000
+	DiscordMaxMessageLength = 2000
+	SMSMaxMessageLength     = 160
+)
+
+// chunkFormat prefixes the numbered chunks
+const chunkFormat = "(%d/%d) "
+
+// ChunkMessage splits a message longer than limit characters in sequential
+// chunks numbered as (1/3), a message fitting the limit is returned as is
+func ChunkMessage(message string, limit int) []string {
+	return numberedChunks(message, limit, chunkFormat)
+}
+
+// numberedChunks splits the message in chunks prefixed with the format of
+// their number and the total, which must leave room for the text
+func numberedChunks(message string, limit int, format string) []string {
+	if limit <= 0 || utf8.RuneCountInString(message) <= limit {
+		return []string{message}
+	}
+	// the prefix grows with the digits of the total
+	for digits, max := 1, 9; ; digits, max = digits+1, max*10+9 {
+		prefix := utf8.RuneCountInString(fmt.Sprintf(format, max, max))
+		if prefix >= limit {
+			return splitMessage(message, limit)
+		}
+		chunks := splitMessage(message, limit-prefix)
+		if len(chunks) > max {
+			continue
+		}
+		for i := range chunks {
+			chunks[i] = fmt.Sprintf(format, i+1, len(chunks)) + chunks[i]
+		}
+		return chunks
+	}
+}
+
+// splitMessage splits the message in chunks of at most limit characters,
+// breaking at the last newline or space of a chunk when there is one
+func splitMessage(message string, limit int) []string {
+	runes := []rune(message)
+	if limit <= 0 || len(runes) <= limit {
+		return []string{message}
+	}
+
+	var chunks []string
+	for len(runes) > limit {
+		cut := limit
+		chunk := string(runes[:limit])
+		if i := strings.LastIndexAny(chunk, "\n "); i > 0 {
+			cut = len([]rune(chunk[:i])) + 1
+		}
+		chunks = append(chunks, strings.TrimRight(string(runes[:cut]), "\n "))
+		runes = runes[cut:]
+	}
+	if last := strings.TrimRight(string(runes), "\n "); last != "" {
+		chunks = append(chunks, last)
+	}
+	return chunks
+}
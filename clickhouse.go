@@ -0,0 +1,134 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// DefaultClickHouseTimeout to conclude operations
+const DefaultClickHouseTimeout = 10 * time.Second
+
+const clickHouseTimeFormat = "2006-01-02 15:04:05"
+
+// ClickHouseClient inserts notifications into a ClickHouse table over the http interface
+type ClickHouseClient struct {
+	client   *retryablehttp.Client
+	URL      string
+	Database string
+	Table    string
+	Username string
+	Password string
+	// CreateTable creates the table with time and message columns if missing
+	CreateTable bool
+	BatchSize   int
+	TimeOut     time.Duration
+
+	batch recordBatch
+	// tableCreated is set once the table creation succeeded, failed
+	// creations are retried on the next insert
+	tableMutex   sync.Mutex
+	tableCreated bool
+}
+
+// clickHouseRow is a row in JSONEachRow format
+type clickHouseRow struct {
+	Time    string `json:"time"`
+	Message string `json:"message"`
+}
+
+// SendInfo inserts the message, with batching enabled rows are inserted once the batch is full
+func (cc *ClickHouseClient) SendInfo(message string) error {
+	return cc.SendInfoCtx(context.Background(), message)
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (cc *ClickHouseClient) SendInfoCtx(ctx context.Context, message string) error {
+	if records := cc.batch.add(newRecord(message), cc.BatchSize); len(records) > 0 {
+		return cc.insert(ctx, records)
+	}
+	return nil
+}
+
+// Flush inserts the pending batched rows
+func (cc *ClickHouseClient) Flush() error {
+	if records := cc.batch.drain(); len(records) > 0 {
+		return cc.insert(context.Background(), records)
+	}
+	return nil
+}
+
+// createTable creates the table unless it was already created
+func (cc *ClickHouseClient) createTable(ctx context.Context) error {
+	if !cc.CreateTable {
+		return nil
+	}
+	cc.tableMutex.Lock()
+	defer cc.tableMutex.Unlock()
+
+	if cc.tableCreated {
+		return nil
+	}
+	query := "CREATE TABLE IF NOT EXISTS " + cc.Table +
+		" (time DateTime, message String) ENGINE = MergeTree ORDER BY time"
+	if err := cc.query(ctx, query, nil); err != nil {
+		return err
+	}
+	cc.tableCreated = true
+	return nil
+}
+
+func (cc *ClickHouseClient) insert(ctx context.Context, records []record) error {
+	if err := cc.createTable(ctx); err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, r := range records {
+		if err := enc.Encode(clickHouseRow{Time: r.Time.Format(clickHouseTimeFormat), Message: r.Message}); err != nil {
+			return err
+		}
+	}
+	return cc.query(ctx, "INSERT INTO "+cc.Table+" FORMAT JSONEachRow", body.Bytes())
+}
+
+func (cc *ClickHouseClient) query(ctx context.Context, query string, body []byte) error {
+	params := url.Values{}
+	params.Set("query", query)
+	if cc.Database != "" {
+		params.Set("database", cc.Database)
+	}
+	req, err := retryablehttp.NewRequest(http.MethodPost, strings.TrimSuffix(cc.URL, "/")+"/?"+params.Encode(), body)
+	if err != nil {
+		return err
+	}
+	if cc.Username != "" {
+		req.Header.Add("X-ClickHouse-User", cc.Username)
+		req.Header.Add("X-ClickHouse-Key", cc.Password)
+	}
+
+	resp, err := doTimeout(cc.client, req.WithContext(ctx), cc.TimeOut)
+	if err != nil {
+		return err
+	}
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck // silent fail
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newResponseError(resp, buf)
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+package notify
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// DefaultCloudEventsTimeout to conclude operations
+const DefaultCloudEventsTimeout = 5 * time.Second
+
+// CloudEvents defaults
+const (
+	CloudEventsSpecVersion = "1.0"
+	DefaultCloudEventsType = "io.projectdiscovery.notify.message"
+	DefaultCloudEventsSrc  = "notify"
+	cloudEventsContentType = "application/cloudevents+json"
+	maxCloudEventSize      = 1 << 20
+)
+
+// CloudEventsMode is the http content mode of the events
+type CloudEventsMode string
+
+// CloudEvents http content modes
+const (
+	// CloudEventsBinary carries the attributes in ce- headers and the message as body
+	CloudEventsBinary CloudEventsMode = "binary"
+	// CloudEventsStructured carries the whole event as json
+	CloudEventsStructured CloudEventsMode = "structured"
+)
+
+// ErrInvalidCloudEvent is returned for requests which aren't cloudevents
+var ErrInvalidCloudEvent = errors.New("invalid cloudevent")
+
+// CloudEventsClient posts notifications as cloudevents
+type CloudEventsClient struct {
+	client  *retryablehttp.Client
+	URL     string
+	Source  string
+	Type    string
+	Mode    CloudEventsMode
+	TimeOut time.Duration
+}
+
+// CloudEvent json structure of structured mode
+type CloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            string          `json:"time,omitempty"`
+	DataContentType string          `json:"datacontenttype,omitempty"`
+	Data            json.RawMessage `json:"data,omitempty"`
+}
+
+// SendInfo to cloudevents
+func (cc *CloudEventsClient) SendInfo(message string) error {
+	return cc.SendInfoCtx(context.Background(), message)
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (cc *CloudEventsClient) SendInfoCtx(ctx context.Context, message string) error {
+	event, err := cc.newEvent(message)
+	if err != nil {
+		return err
+	}
+	return cc.SendEventCtx(ctx, event)
+}
+
+func (cc *CloudEventsClient) newEvent(message string) (*CloudEvent, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+	source, eventType := cc.Source, cc.Type
+	if source == "" {
+		source = DefaultCloudEventsSrc
+	}
+	if eventType == "" {
+		eventType = DefaultCloudEventsType
+	}
+	return &CloudEvent{
+		SpecVersion:     CloudEventsSpecVersion,
+		ID:              hex.EncodeToString(buf),
+		Source:          source,
+		Type:            eventType,
+		Time:            time.Now().UTC().Format(time.RFC3339Nano),
+		DataContentType: "text/plain",
+		Data:            data,
+	}, nil
+}
+
+// SendEvent in the configured content mode
+func (cc *CloudEventsClient) SendEvent(event *CloudEvent) error {
+	return cc.SendEventCtx(context.Background(), event)
+}
+
+// SendEventCtx is SendEvent canceled with the context
+func (cc *CloudEventsClient) SendEventCtx(ctx context.Context, event *CloudEvent) error {
+	var body []byte
+	headers := make(map[string]string)
+	if cc.Mode == CloudEventsStructured {
+		var err error
+		if body, err = json.Marshal(event); err != nil {
+			return err
+		}
+		headers["Content-Type"] = cloudEventsContentType
+	} else {
+		body = event.Data
+		if event.DataContentType == "text/plain" {
+			var text string
+			if err := json.Unmarshal(event.Data, &text); err != nil {
+				return err
+			}
+			body = []byte(text)
+		}
+		headers["Content-Type"] = event.DataContentType
+		headers["ce-specversion"] = event.SpecVersion
+		headers["ce-id"] = event.ID
+		headers["ce-source"] = event.Source
+		headers["ce-type"] = event.Type
+		if event.Subject != "" {
+			headers["ce-subject"] = event.Subject
+		}
+		if event.Time != "" {
+			headers["ce-time"] = event.Time
+		}
+	}
+
+	req, err := retryablehttp.NewRequest(http.MethodPost, cc.URL, body)
+	if err != nil {
+		return err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := doTimeout(cc.client, req.WithContext(ctx), cc.TimeOut)
+	if err != nil {
+		return err
+	}
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck // silent fail
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return newResponseError(resp, buf)
+	}
+	return nil
+}
+
+// ParseCloudEvent reads a binary or structured mode cloudevent from the request
+func ParseCloudEvent(r *http.Request) (*CloudEvent, error) {
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxCloudEventSize))
+	if err != nil {
+		return nil, err
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	if strings.HasPrefix(contentType, cloudEventsContentType) {
+		var event CloudEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			return nil, err
+		}
+		if event.SpecVersion == "" || event.ID == "" || event.Source == "" || event.Type == "" {
+			return nil, ErrInvalidCloudEvent
+		}
+		return &event, nil
+	}
+
+	event := &CloudEvent{
+		SpecVersion:     r.Header.Get("ce-specversion"),
+		ID:              r.Header.Get("ce-id"),
+		Source:          r.Header.Get("ce-source"),
+		Type:            r.Header.Get("ce-type"),
+		Subject:         r.Header.Get("ce-subject"),
+		Time:            r.Header.Get("ce-time"),
+		DataContentType: contentType,
+	}
+	if event.SpecVersion == "" || event.ID == "" || event.Source == "" || event.Type == "" {
+		return nil, ErrInvalidCloudEvent
+	}
+	if json.Valid(body) && strings.Contains(contentType, "json") {
+		event.Data = body
+	} else if event.Data, err = json.Marshal(string(body)); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// Message of the event, string data as is and other data as json
+func (e *CloudEvent) Message() string {
+	var text string
+	if err := json.Unmarshal(e.Data, &text); err == nil {
+		return text
+	}
+	return string(e.Data)
+}
+
+// CloudEventsHandler relays received cloudevents to the enabled providers
+func (n *Notify) CloudEventsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		event, err := ParseCloudEvent(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := n.EnqueueSource(event.Source, event.Message()); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	})
+}
@@ -0,0 +1,96 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCoalesceMaxMessages emits a burst early once it holds that many messages
+const DefaultCoalesceMaxMessages = 100
+
+// coalescer merges the messages of a source arriving within a window
+type coalescer struct {
+	sync.Mutex
+	window      time.Duration
+	maxMessages int
+	pending     map[string]*burst
+	emit        func(message string) error
+	// emitting tracks the bursts fired and not yet emitted
+	emitting sync.WaitGroup
+}
+
+// burst is the set of messages waiting for the window of a source to expire
+type burst struct {
+	messages []string
+	timer    *time.Timer
+}
+
+func newCoalescer(window time.Duration, maxMessages int, emit func(message string) error) *coalescer {
+	if maxMessages <= 0 {
+		maxMessages = DefaultCoalesceMaxMessages
+	}
+	return &coalescer{window: window, maxMessages: maxMessages, pending: make(map[string]*burst), emit: emit}
+}
+
+func (c *coalescer) add(source, message string) {
+	c.Lock()
+	b, ok := c.pending[source]
+	if !ok {
+		b = &burst{}
+		c.pending[source] = b
+		b.timer = time.AfterFunc(c.window, func() { c.fire(source, b) })
+	}
+	b.messages = append(b.messages, message)
+	full := len(b.messages) >= c.maxMessages
+	c.Unlock()
+
+	if full {
+		c.fire(source, b)
+	}
+}
+
+// fire emits the burst unless it was already emitted
+func (c *coalescer) fire(source string, b *burst) {
+	c.Lock()
+	if c.pending[source] != b {
+		c.Unlock()
+		return
+	}
+	delete(c.pending, source)
+	b.timer.Stop()
+	c.emitting.Add(1)
+	c.Unlock()
+	defer c.emitting.Done()
+
+	//nolint:errcheck // outcome is tracked by stats and events
+	c.emit(c.combine(source, b.messages))
+}
+
+// flush emits every pending burst and waits for the fired ones
+func (c *coalescer) flush() {
+	c.Lock()
+	pending := c.pending
+	c.pending = make(map[string]*burst)
+	c.Unlock()
+
+	for source, b := range pending {
+		b.timer.Stop()
+		//nolint:errcheck // outcome is tracked by stats and events
+		c.emit(c.combine(source, b.messages))
+	}
+	c.emitting.Wait()
+}
+
+func (c *coalescer) combine(source string, messages []string) string {
+	if len(messages) == 1 {
+		return messages[0]
+	}
+	header := fmt.Sprintf("%d new messages", len(messages))
+	if source != "" {
+		header += " from " + source
+	}
+	header += fmt.Sprintf(" in the last %s:\n", c.window)
+	return header + strings.Join(messages, "\n")
+}
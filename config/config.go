@@ -0,0 +1,348 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Shopify/yaml"
+	"github.com/projectdiscovery/notify"
+)
+
+// DefaultFilename of the provider configuration
+const DefaultFilename = "provider-config.yaml"
+
+// fileSuffix of the settings read from a file
+const fileSuffix = "_file"
+
+var (
+	// ErrUnknownProvider is returned for provider types without options
+	ErrUnknownProvider = errors.New("unknown provider type")
+	// ErrUndefinedVariable is returned for ${VAR} references to unset variables
+	ErrUndefinedVariable = errors.New("undefined environment variable")
+
+	envRegex = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+)
+
+// typeAliases maps the provider types to the options enabling them
+// when they don't match the option name
+var typeAliases = map[string]string{
+	notify.ProviderCustomWebhook: "CustomWebhook",
+	notify.ProviderServiceURL:    "Service",
+}
+
+// settingProviders are the provider types without a bool option, enabled
+// by their required settings
+var settingProviders = map[string]bool{
+	notify.ProviderServiceURL: true,
+}
+
+// Config lists the configured providers
+type Config struct {
+	Providers []*Provider `yaml:"providers"`
+}
+
+// Provider is a configured provider, eg.
+//
+//	providers:
+//	  - id: recon
+//	    type: slack
+//	    webhook_url: https://hooks.slack.com/services/...
+//	    channel: recon
+//	    tags: [scan]
+//
+// The settings are the options of the provider type in snake case
+// without the provider prefix, eg. webhook_url for SlackWebHookURL.
+// ${VAR} in values is replaced by the environment variable and a setting
+// suffixed by _file, eg. token_file, is read from the file at its path.
+// A serviceurl provider is enabled by its urls setting. Schema describes
+// the settings of every provider type.
+type Provider struct {
+	ID       string                 `yaml:"id"`
+	Type     string                 `yaml:"type"`
+	Tags     []string               `yaml:"tags,omitempty"`
+	Settings map[string]interface{} `yaml:",inline"`
+}
+
+// DefaultPath returns the path of the configuration in the user config directory
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "notify", DefaultFilename), nil
+}
+
+// Load reads the configuration file
+func Load(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(bytes.NewReader(data))
+}
+
+// Parse decodes and validates a yaml configuration
+func Parse(r io.Reader) (*Config, error) {
+	var config Config
+	if err := yaml.NewDecoder(r).Decode(&config); err != nil && err != io.EOF {
+		return nil, err
+	}
+	if problems := config.Validate(); len(problems) > 0 {
+		return nil, problems[0]
+	}
+	return &config, nil
+}
+
+// Options returns the notify options enabling the provider with its settings
+func (p *Provider) Options() (*notify.Options, error) {
+	options, problems := p.options()
+	if len(problems) > 0 {
+		return nil, problems[0]
+	}
+	return options, nil
+}
+
+// options decodes the settings reporting every problem in the order of the settings
+func (p *Provider) options() (*notify.Options, []*ConfigError) {
+	options := &notify.Options{}
+	value := reflect.ValueOf(options).Elem()
+
+	prefix, ok := typeAliases[p.Type]
+	if !ok {
+		prefix = p.Type
+	}
+	if !settingProviders[p.Type] {
+		enable := fieldByKey(value, prefix, "")
+		if !enable.IsValid() || enable.Kind() != reflect.Bool {
+			return nil, []*ConfigError{{Provider: p.ID, Err: fmt.Errorf("%w %q", ErrUnknownProvider, p.Type)}}
+		}
+		enable.SetBool(true)
+	}
+
+	keys := make([]string, 0, len(p.Settings))
+	for key := range p.Settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var problems []*ConfigError
+	for _, key := range keys {
+		if err := p.setting(value, prefix, key); err != nil {
+			problems = append(problems, &ConfigError{Provider: p.ID, Key: key, Err: err})
+		}
+	}
+	for _, alternatives := range requiredSettings[p.Type] {
+		set := false
+		for _, name := range alternatives {
+			if field := fieldByKey(value, prefix, name); field.IsValid() && !field.IsZero() {
+				set = true
+			}
+		}
+		if !set {
+			err := ErrMissingSetting
+			if len(alternatives) > 1 {
+				names := make([]string, len(alternatives))
+				for i, name := range alternatives {
+					names[i] = snakeCase(name)
+				}
+				err = fmt.Errorf("%w, one of %s", ErrMissingSetting, strings.Join(names, ", "))
+			}
+			problems = append(problems, &ConfigError{Provider: p.ID, Key: snakeCase(alternatives[0]), Err: err})
+		}
+	}
+	return options, problems
+}
+
+// setting assigns the setting to its option
+func (p *Provider) setting(value reflect.Value, prefix, key string) error {
+	setting, err := expand(p.Settings[key])
+	if err != nil {
+		return err
+	}
+	field := fieldByKey(value, prefix, key)
+	if !field.IsValid() && strings.HasSuffix(key, fileSuffix) {
+		if setting, err = readSecret(setting); err != nil {
+			return err
+		}
+		field = fieldByKey(value, prefix, strings.TrimSuffix(key, fileSuffix))
+	}
+	if !field.IsValid() {
+		return fmt.Errorf("unknown %s setting", p.Type)
+	}
+	if err := assign(field, setting); err != nil {
+		return fmt.Errorf("invalid %s setting: %w", p.Type, err)
+	}
+	return nil
+}
+
+// New returns a notifier of the provider
+func (p *Provider) New() (*notify.Notify, error) {
+	options, err := p.Options()
+	if err != nil {
+		return nil, err
+	}
+	return notify.NewWithOptions(options)
+}
+
+// HasTag reports whether the provider has one of the tags
+func (p *Provider) HasTag(tags ...string) bool {
+	for _, tag := range tags {
+		for _, providerTag := range p.Tags {
+			if tag == providerTag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// MultiNotifier returns a notifier sending to the providers by id, having one of
+// the tags, or to all of them when no tag is given. Close releases the providers.
+func (c *Config) MultiNotifier(tags ...string) (*notify.MultiNotifier, error) {
+	multi := notify.NewMultiNotifier()
+	for _, provider := range c.Providers {
+		if len(tags) > 0 && !provider.HasTag(tags...) && !contains(tags, provider.ID) {
+			continue
+		}
+		n, err := provider.New()
+		if err != nil {
+			multi.Close()
+			return nil, fmt.Errorf("%s: %w", provider.ID, err)
+		}
+		notifier, ok := n.Notifier(provider.Type)
+		if !ok {
+			n.Close()
+			multi.Close()
+			return nil, &ConfigError{Provider: provider.ID, Err: fmt.Errorf("%w %q", ErrUnknownProvider, provider.Type)}
+		}
+		multi.Add(provider.ID, &providerNotifier{Notifier: notifier, engine: n})
+	}
+	return multi, nil
+}
+
+// providerNotifier is the notifier of a provider owning its engine
+type providerNotifier struct {
+	notify.Notifier
+	engine *notify.Notify
+}
+
+// Close drains the queue of the provider engine
+func (p *providerNotifier) Close() {
+	p.engine.Close()
+}
+
+// fieldByKey returns the option named prefix and key, compared case
+// insensitively without the separators
+func fieldByKey(value reflect.Value, prefix, key string) reflect.Value {
+	name := normalize(prefix + key)
+	field := value.FieldByNameFunc(func(field string) bool {
+		return strings.ToLower(field) == name
+	})
+	return field
+}
+
+func normalize(key string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(key))
+}
+
+// assign converts the yaml value to the type of the field
+func assign(field reflect.Value, setting interface{}) error {
+	data, err := json.Marshal(jsonValue(setting))
+	if err != nil {
+		return err
+	}
+	target := reflect.New(field.Type())
+	if err := json.Unmarshal(data, target.Interface()); err != nil {
+		return err
+	}
+	field.Set(target.Elem())
+	return nil
+}
+
+// jsonValue converts the yaml maps keyed by interface{} to json encodable ones
+func jsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			converted[fmt.Sprint(key)] = jsonValue(item)
+		}
+		return converted
+	case []interface{}:
+		for i, item := range v {
+			v[i] = jsonValue(item)
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+// expand replaces the ${VAR} references of the string values
+func expand(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		var err error
+		expanded := envRegex.ReplaceAllStringFunc(v, func(reference string) string {
+			name := envRegex.FindStringSubmatch(reference)[1]
+			env, ok := os.LookupEnv(name)
+			if !ok && err == nil {
+				err = fmt.Errorf("%w %s", ErrUndefinedVariable, name)
+			}
+			return env
+		})
+		return expanded, err
+	case []interface{}:
+		expanded := make([]interface{}, len(v))
+		for i, item := range v {
+			var err error
+			if expanded[i], err = expand(item); err != nil {
+				return nil, err
+			}
+		}
+		return expanded, nil
+	case map[interface{}]interface{}:
+		expanded := make(map[interface{}]interface{}, len(v))
+		for key, item := range v {
+			var err error
+			if expanded[key], err = expand(item); err != nil {
+				return nil, err
+			}
+		}
+		return expanded, nil
+	default:
+		return value, nil
+	}
+}
+
+// readSecret returns the content of the file at path without the trailing newline
+func readSecret(path interface{}) (interface{}, error) {
+	name, ok := path.(string)
+	if !ok {
+		return nil, errors.New("file path is not a string")
+	}
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
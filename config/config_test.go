@@ -0,0 +1,104 @@
+package config
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestExpand(t *testing.T) {
+	os.Setenv("NOTIFY_TEST_HOST", "hooks.example.com") //nolint:errcheck // silent fail
+	os.Setenv("NOTIFY_TEST_EMPTY", "")                 //nolint:errcheck // silent fail
+	defer os.Unsetenv("NOTIFY_TEST_HOST")              //nolint:errcheck // silent fail
+	defer os.Unsetenv("NOTIFY_TEST_EMPTY")             //nolint:errcheck // silent fail
+
+	tests := []struct {
+		value interface{}
+		want  interface{}
+	}{
+		{"https://${NOTIFY_TEST_HOST}/${NOTIFY_TEST_HOST}", "https://hooks.example.com/hooks.example.com"},
+		{"empty${NOTIFY_TEST_EMPTY}", "empty"},
+		{"$NOTIFY_TEST_HOST and $${}", "$NOTIFY_TEST_HOST and $${}"},
+		{[]interface{}{"${NOTIFY_TEST_HOST}", 1}, []interface{}{"hooks.example.com", 1}},
+		{map[interface{}]interface{}{"host": "${NOTIFY_TEST_HOST}"}, map[interface{}]interface{}{"host": "hooks.example.com"}},
+		{true, true},
+	}
+	for _, test := range tests {
+		got, err := expand(test.value)
+		if err != nil {
+			t.Errorf("expand(%v) returned %v", test.value, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("expand(%v) = %v, want %v", test.value, got, test.want)
+		}
+	}
+}
+
+func TestExpandUndefined(t *testing.T) {
+	os.Unsetenv("NOTIFY_TEST_UNDEFINED") //nolint:errcheck // silent fail
+	for _, value := range []interface{}{"${NOTIFY_TEST_UNDEFINED}", []interface{}{"ok", "${NOTIFY_TEST_UNDEFINED}"}} {
+		if _, err := expand(value); !errors.Is(err, ErrUndefinedVariable) {
+			t.Errorf("expand(%v) = %v, want ErrUndefinedVariable", value, err)
+		}
+	}
+}
+
+func TestProviderSettingFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "notify-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir) //nolint:errcheck // silent fail
+	path := filepath.Join(dir, "api_key")
+	if err := ioutil.WriteFile(path, []byte("123:secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	os.Setenv("NOTIFY_TEST_DIR", dir)    //nolint:errcheck // silent fail
+	defer os.Unsetenv("NOTIFY_TEST_DIR") //nolint:errcheck // silent fail
+
+	provider := &Provider{ID: "alerts", Type: "telegram", Settings: map[string]interface{}{
+		"api_key_file": "${NOTIFY_TEST_DIR}/api_key",
+		"chat_id":      "42",
+	}}
+	options, err := provider.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !options.Telegram || options.TelegramAPIKey != "123:secret" || options.TelegramChatID != "42" {
+		t.Errorf("Options() = telegram %v, api key %q, chat id %q", options.Telegram, options.TelegramAPIKey, options.TelegramChatID)
+	}
+
+	provider.Settings["api_key_file"] = filepath.Join(dir, "missing")
+	if _, err := provider.Options(); !os.IsNotExist(errors.Unwrap(err)) {
+		t.Errorf("Options() with a missing secret file = %v, want a not exist error", err)
+	}
+	provider.Settings["api_key_file"] = 42
+	var configErr *ConfigError
+	if _, err := provider.Options(); !errors.As(err, &configErr) || configErr.Key != "api_key_file" {
+		t.Errorf("Options() with a non string file path = %v, want an api_key_file error", err)
+	}
+}
+
+func TestProviderOptionsProblems(t *testing.T) {
+	provider := &Provider{ID: "alerts", Type: "slack", Settings: map[string]interface{}{
+		"channel":  "${NOTIFY_TEST_UNDEFINED}",
+		"unknown":  "value",
+		"username": "notify",
+	}}
+	_, problems := provider.options()
+
+	var keys []string
+	for _, problem := range problems {
+		keys = append(keys, problem.Key)
+	}
+	if want := []string{"channel", "unknown", "webhook_url"}; !reflect.DeepEqual(keys, want) {
+		t.Fatalf("options() problems on %v, want %v", keys, want)
+	}
+	if !errors.Is(problems[0], ErrUndefinedVariable) || !errors.Is(problems[2], ErrMissingSetting) {
+		t.Errorf("options() = %v", problems)
+	}
+}
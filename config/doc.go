@@ -0,0 +1,2 @@
+// Package config loads provider definitions from a yaml file
+package config
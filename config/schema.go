@@ -0,0 +1,234 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/Shopify/yaml"
+	"github.com/projectdiscovery/notify"
+)
+
+// ErrMissingSetting is returned for providers without their credentials
+var ErrMissingSetting = errors.New("missing required setting")
+
+// requiredSettings are the options a provider type can't send without, one
+// of the alternatives of each entry must be set
+var requiredSettings = map[string][][]string{
+	notify.ProviderSlack:      {{"WebHookURL", "WebHookURLs", "Token"}},
+	notify.ProviderDiscord:    {{"WebHookURL", "WebHookURLs"}},
+	notify.ProviderTelegram:   {{"APIKey"}, {"ChatID"}},
+	notify.ProviderServiceURL: {{"URLs"}},
+}
+
+// ConfigError is a problem of the provider configuration
+type ConfigError struct {
+	// Provider is the id of the provider at fault, Key its setting if any
+	Provider string
+	Key      string
+	Err      error
+}
+
+// Error returns the problem description
+func (e *ConfigError) Error() string {
+	var parts []string
+	for _, part := range []string{e.Provider, e.Key} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(append(parts, e.Err.Error()), ": ")
+}
+
+// Unwrap returns the cause of the problem
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// ValidateConfig checks the configuration file reporting unknown provider
+// types and settings, type errors and missing credentials
+func ValidateConfig(path string) ([]*ConfigError, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var config Config
+	if err := yaml.NewDecoder(bytes.NewReader(data)).Decode(&config); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return config.Validate(), nil
+}
+
+// Validate reports the problems of every provider
+func (c *Config) Validate() []*ConfigError {
+	var problems []*ConfigError
+	ids := make(map[string]bool, len(c.Providers))
+	for i, provider := range c.Providers {
+		if provider.ID == "" {
+			provider.ID = fmt.Sprintf("%s-%d", provider.Type, i)
+		}
+		if ids[provider.ID] {
+			problems = append(problems, &ConfigError{Provider: provider.ID, Err: errors.New("duplicate provider id")})
+			continue
+		}
+		ids[provider.ID] = true
+		_, providerProblems := provider.options()
+		problems = append(problems, providerProblems...)
+	}
+	return problems
+}
+
+// Schema returns the json schema of the configuration file, generated from
+// the provider options. The settings are matched case insensitively without
+// the separators, the schema names them in snake case.
+func Schema() ([]byte, error) {
+	providers := providerSettings()
+	types := make([]string, 0, len(providers))
+	for providerType := range providers {
+		types = append(types, providerType)
+	}
+	sort.Strings(types)
+
+	var rules []interface{}
+	for _, providerType := range types {
+		properties := make(map[string]interface{})
+		for name, field := range providers[providerType] {
+			properties[snakeCase(name)] = map[string]interface{}{"type": jsonType(field.Type)}
+		}
+		// the keys of the provider override the settings of the same name
+		properties["id"] = map[string]interface{}{"type": "string"}
+		properties["type"] = map[string]interface{}{"const": providerType}
+		properties["tags"] = map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}}
+		then := map[string]interface{}{
+			"properties":           properties,
+			"patternProperties":    map[string]interface{}{fileSuffix + "$": map[string]interface{}{"type": "string"}},
+			"additionalProperties": false,
+		}
+		var required []interface{}
+		for _, alternatives := range requiredSettings[providerType] {
+			var anyOf []interface{}
+			for _, name := range alternatives {
+				key := snakeCase(name)
+				anyOf = append(anyOf,
+					map[string]interface{}{"required": []string{key}},
+					map[string]interface{}{"required": []string{key + fileSuffix}},
+				)
+			}
+			required = append(required, map[string]interface{}{"anyOf": anyOf})
+		}
+		if len(required) > 0 {
+			then["allOf"] = required
+		}
+		rules = append(rules, map[string]interface{}{
+			"if":   map[string]interface{}{"properties": map[string]interface{}{"type": map[string]interface{}{"const": providerType}}},
+			"then": then,
+		})
+	}
+
+	schema := map[string]interface{}{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"$id":     "https://github.com/projectdiscovery/notify/provider-config.schema.json",
+		"title":   "notify provider configuration",
+		"type":    "object",
+		"properties": map[string]interface{}{
+			"providers": map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type":     "object",
+					"required": []string{"type"},
+					"properties": map[string]interface{}{
+						"type": map[string]interface{}{"enum": types},
+					},
+					"allOf": rules,
+				},
+			},
+		},
+		"additionalProperties": false,
+	}
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// providerSettings returns the settings of the provider types keyed by the
+// option name without the provider prefix. The options lay out each provider
+// as its settings followed by the bool enabling it, eg. SlackWebHookURL to Slack.
+func providerSettings() map[string]map[string]reflect.StructField {
+	optionsType := reflect.TypeOf(notify.Options{})
+	prefixTypes := make(map[string]string, len(typeAliases))
+	for providerType, prefix := range typeAliases {
+		prefixTypes[prefix] = providerType
+	}
+
+	providers := make(map[string]map[string]reflect.StructField)
+	for i := 0; i < optionsType.NumField(); i++ {
+		enable := optionsType.Field(i)
+		if enable.Type.Kind() != reflect.Bool || i == 0 || !strings.HasPrefix(optionsType.Field(i-1).Name, enable.Name) {
+			continue
+		}
+		providerType, ok := prefixTypes[enable.Name]
+		if !ok {
+			providerType = strings.ToLower(enable.Name)
+		}
+		settings := make(map[string]reflect.StructField)
+		for j := i - 1; j >= 0 && strings.HasPrefix(optionsType.Field(j).Name, enable.Name); j-- {
+			field := optionsType.Field(j)
+			settings[strings.TrimPrefix(field.Name, enable.Name)] = field
+		}
+		providers[providerType] = settings
+	}
+
+	for providerType := range settingProviders {
+		prefix := typeAliases[providerType]
+		settings := make(map[string]reflect.StructField)
+		for i := 0; i < optionsType.NumField(); i++ {
+			if field := optionsType.Field(i); strings.HasPrefix(field.Name, prefix) {
+				settings[strings.TrimPrefix(field.Name, prefix)] = field
+			}
+		}
+		providers[providerType] = settings
+	}
+	return providers
+}
+
+// snakeCase converts an option name, eg. WebHookURLs to webhook_urls
+func snakeCase(name string) string {
+	runes := []rune(strings.Replace(name, "WebHook", "Webhook", -1))
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			previous := runes[i-1]
+			// the s of a plural acronym isn't a word, eg. URLs
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1]) && !(runes[i+1] == 's' && i+2 == len(runes))
+			if !unicode.IsUpper(previous) || nextLower {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// jsonType returns the json schema type of an option
+func jsonType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	default:
+		return "string"
+	}
+}
@@ -3,3 +3,51 @@ package notify
 const (
 	ok = "ok"
 )
+
+// Provider names used to identify webhooks in stats and events
+const (
+	ProviderSlack         = "slack"
+	ProviderDiscord       = "discord"
+	ProviderTelegram      = "telegram"
+	ProviderS3            = "s3"
+	ProviderElasticsearch = "elasticsearch"
+	ProviderSplunk        = "splunk"
+	ProviderLoki          = "loki"
+	ProviderClickHouse    = "clickhouse"
+	ProviderGrafana       = "grafana"
+	ProviderInfluxDB      = "influxdb"
+	ProviderChime         = "chime"
+	ProviderBitrix24      = "bitrix24"
+	ProviderTeams         = "teams"
+	ProviderCloudEvents   = "cloudevents"
+	ProviderMattermost    = "mattermost"
+	ProviderGoogleChat    = "googlechat"
+	ProviderPushover      = "pushover"
+	ProviderGotify        = "gotify"
+	ProviderNtfy          = "ntfy"
+	ProviderMatrix        = "matrix"
+	ProviderSignal        = "signal"
+	ProviderSNS           = "sns"
+	ProviderEmail         = "email"
+	ProviderPagerDuty     = "pagerduty"
+	ProviderOpsgenie      = "opsgenie"
+	ProviderZulip         = "zulip"
+	ProviderXMPP          = "xmpp"
+	ProviderIRC           = "irc"
+	ProviderDingTalk      = "dingtalk"
+	ProviderWeCom         = "wecom"
+	ProviderLark          = "lark"
+	ProviderBark          = "bark"
+	ProviderWebex         = "webex"
+	ProviderJira          = "jira"
+	ProviderGitHub        = "github"
+	ProviderGitLab        = "gitlab"
+	ProviderKafka         = "kafka"
+	ProviderNATS          = "nats"
+	ProviderDatadog       = "datadog"
+	ProviderSentry        = "sentry"
+	ProviderCustomWebhook = "webhook"
+	ProviderSES           = "ses"
+	ProviderNextcloudTalk = "nextcloudtalk"
+	ProviderServiceURL    = "serviceurl"
+)
@@ -0,0 +1,130 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// DefaultDatadogTimeout to conclude operations
+const DefaultDatadogTimeout = 5 * time.Second
+
+// DefaultDatadogSite is the us1 site, other regions use eg. datadoghq.eu or us3.datadoghq.com
+const DefaultDatadogSite = "datadoghq.com"
+
+// datadog limits of the event title and text
+const (
+	datadogMaxTitle = 100
+	datadogMaxText  = 4000
+)
+
+// Datadog alert types of the severity helpers
+const (
+	DatadogAlertInfo    = "info"
+	DatadogAlertWarning = "warning"
+	DatadogAlertError   = "error"
+	DatadogAlertSuccess = "success"
+)
+
+// DatadogClient posts events with the events api
+type DatadogClient struct {
+	client *retryablehttp.Client
+	// Site of the account, DefaultDatadogSite if empty
+	Site   string
+	APIKey string
+	Tags   []string
+	// AggregationKey groups the events in the event stream
+	AggregationKey string
+	TimeOut        time.Duration
+}
+
+// DatadogEvent json structure
+type DatadogEvent struct {
+	Title          string   `json:"title"`
+	Text           string   `json:"text"`
+	AlertType      string   `json:"alert_type,omitempty"`
+	Tags           []string `json:"tags,omitempty"`
+	AggregationKey string   `json:"aggregation_key,omitempty"`
+	SourceTypeName string   `json:"source_type_name,omitempty"`
+	DateHappened   int64    `json:"date_happened,omitempty"`
+}
+
+// SendInfo to datadog
+func (dc *DatadogClient) SendInfo(message string) error {
+	return dc.SendInfoCtx(context.Background(), message)
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (dc *DatadogClient) SendInfoCtx(ctx context.Context, message string) error {
+	return dc.send(ctx, message, DatadogAlertInfo)
+}
+
+// SendWarning to datadog
+func (dc *DatadogClient) SendWarning(message string) error {
+	return dc.send(context.Background(), message, DatadogAlertWarning)
+}
+
+// SendError to datadog
+func (dc *DatadogClient) SendError(message string) error {
+	return dc.send(context.Background(), message, DatadogAlertError)
+}
+
+func (dc *DatadogClient) send(ctx context.Context, message, alertType string) error {
+	text := message
+	if runes := []rune(text); len(runes) > datadogMaxText {
+		text = string(runes[:datadogMaxText])
+	}
+	return dc.SendDatadogEventCtx(ctx, &DatadogEvent{
+		Title:          issueSummary(message, datadogMaxTitle),
+		Text:           text,
+		AlertType:      alertType,
+		Tags:           dc.Tags,
+		AggregationKey: dc.AggregationKey,
+		SourceTypeName: "notify",
+		DateHappened:   time.Now().Unix(),
+	})
+}
+
+// SendDatadogEvent with json structure
+func (dc *DatadogClient) SendDatadogEvent(event *DatadogEvent) error {
+	return dc.SendDatadogEventCtx(context.Background(), event)
+}
+
+// SendDatadogEventCtx is SendDatadogEvent canceled with the context
+func (dc *DatadogClient) SendDatadogEventCtx(ctx context.Context, event *DatadogEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	site := dc.Site
+	if site == "" {
+		site = DefaultDatadogSite
+	}
+	req, err := retryablehttp.NewRequest(http.MethodPost, "https://api."+strings.TrimPrefix(site, "api.")+"/api/v1/events", body)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("DD-API-KEY", dc.APIKey)
+
+	resp, err := doTimeout(dc.client, req.WithContext(ctx), dc.TimeOut)
+	if err != nil {
+		return err
+	}
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck // silent fail
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return newResponseError(resp, buf)
+	}
+	return nil
+}
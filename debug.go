@@ -0,0 +1,46 @@
+package notify
+
+import (
+	"encoding/json"
+	"expvar"
+	"net/http"
+)
+
+// DebugPath is the path the debug handler is registered on
+const DebugPath = "/debug/notify"
+
+// debugState is the live state rendered by the debug handler
+type debugState struct {
+	Stats
+	RecentErrors []RecentError           `json:"recent_errors"`
+	Circuits     map[string]CircuitState `json:"circuits,omitempty"`
+}
+
+func (n *Notify) debugState() debugState {
+	return debugState{Stats: n.Stats(), RecentErrors: n.stats.recentErrors(), Circuits: n.circuits.states()}
+}
+
+// PublishExpvar exposes the notifier state as an expvar variable.
+// As with expvar.Publish, it panics if the name is already registered.
+func (n *Notify) PublishExpvar(name string) {
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return n.debugState()
+	}))
+}
+
+// DebugHandler renders queue depth, provider counters, recent errors and
+// circuit breaker states as json
+func (n *Notify) DebugHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		//nolint:errcheck // client went away
+		enc.Encode(n.debugState())
+	})
+}
+
+// RegisterDebugHandler registers the debug handler on the mux at DebugPath
+func (n *Notify) RegisterDebugHandler(mux *http.ServeMux) {
+	mux.Handle(DebugPath, n.DebugHandler())
+}
@@ -0,0 +1,173 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// DefaultDingTalkTimeout to conclude operations
+const DefaultDingTalkTimeout = 5 * time.Second
+
+// DingTalkClient handling dingtalk custom robot webhooks
+type DingTalkClient struct {
+	client     *retryablehttp.Client
+	WebHookURL string
+	// Secret signs the requests of robots with the signature security setting
+	Secret string
+	// AtMobiles and AtUserIDs are mentioned in every message
+	AtMobiles []string
+	AtUserIDs []string
+	AtAll     bool
+	// Markdown sends the messages as markdown, titled with their first line
+	Markdown bool
+	TimeOut  time.Duration
+}
+
+// DingTalkMessage json structure
+type DingTalkMessage struct {
+	MsgType  string            `json:"msgtype"`
+	Text     *DingTalkText     `json:"text,omitempty"`
+	Markdown *DingTalkMarkdown `json:"markdown,omitempty"`
+	At       *DingTalkAt       `json:"at,omitempty"`
+}
+
+// DingTalkText content
+type DingTalkText struct {
+	Content string `json:"content"`
+}
+
+// DingTalkMarkdown content
+type DingTalkMarkdown struct {
+	Title string `json:"title"`
+	Text  string `json:"text"`
+}
+
+// DingTalkAt lists the mentioned members, they must also be referenced as @mobile in the text
+type DingTalkAt struct {
+	AtMobiles []string `json:"atMobiles,omitempty"`
+	AtUserIDs []string `json:"atUserIds,omitempty"`
+	IsAtAll   bool     `json:"isAtAll,omitempty"`
+}
+
+// dingTalkResponse of the robot api, errors are reported with status 200
+type dingTalkResponse struct {
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+}
+
+// SendInfo to dingtalk
+func (dc *DingTalkClient) SendInfo(message string) error {
+	return dc.SendInfoCtx(context.Background(), message)
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (dc *DingTalkClient) SendInfoCtx(ctx context.Context, message string) error {
+	var mentions []string
+	for _, mobile := range dc.AtMobiles {
+		if !strings.Contains(message, "@"+mobile) {
+			mentions = append(mentions, "@"+mobile)
+		}
+	}
+	for _, user := range dc.AtUserIDs {
+		if !strings.Contains(message, "@"+user) {
+			mentions = append(mentions, "@"+user)
+		}
+	}
+	if len(mentions) > 0 {
+		message += "\n" + strings.Join(mentions, " ")
+	}
+
+	dingTalkMessage := &DingTalkMessage{
+		At: &DingTalkAt{AtMobiles: dc.AtMobiles, AtUserIDs: dc.AtUserIDs, IsAtAll: dc.AtAll},
+	}
+	if dc.Markdown {
+		title := message
+		if i := strings.IndexByte(title, '\n'); i >= 0 {
+			title = title[:i]
+		}
+		dingTalkMessage.MsgType = "markdown"
+		dingTalkMessage.Markdown = &DingTalkMarkdown{Title: title, Text: message}
+	} else {
+		dingTalkMessage.MsgType = "text"
+		dingTalkMessage.Text = &DingTalkText{Content: message}
+	}
+	return dc.SendDingTalkNotificationCtx(ctx, dingTalkMessage)
+}
+
+// SendDingTalkNotification with json structure
+func (dc *DingTalkClient) SendDingTalkNotification(dingTalkMessage *DingTalkMessage) error {
+	return dc.SendDingTalkNotificationCtx(context.Background(), dingTalkMessage)
+}
+
+// SendDingTalkNotificationCtx is SendDingTalkNotification canceled with the context
+func (dc *DingTalkClient) SendDingTalkNotificationCtx(ctx context.Context, dingTalkMessage *DingTalkMessage) error {
+	body, err := json.Marshal(dingTalkMessage)
+	if err != nil {
+		return err
+	}
+	URL, err := dc.signedURL(time.Now())
+	if err != nil {
+		return err
+	}
+	req, err := retryablehttp.NewRequest(http.MethodPost, URL, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/json")
+
+	resp, err := doTimeout(dc.client, req.WithContext(ctx), dc.TimeOut)
+	if err != nil {
+		return err
+	}
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck // silent fail
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newResponseError(resp, buf)
+	}
+	var response dingTalkResponse
+	if err := json.Unmarshal(buf, &response); err != nil {
+		return err
+	}
+	if response.ErrCode != 0 {
+		return fmt.Errorf("dingtalk: %s (%d)", response.ErrMsg, response.ErrCode)
+	}
+	return nil
+}
+
+// signedURL appends the timestamp and hmac signature required with a secret,
+// signatures are valid for an hour so they are computed per request
+func (dc *DingTalkClient) signedURL(now time.Time) (string, error) {
+	if dc.Secret == "" {
+		return dc.WebHookURL, nil
+	}
+	u, err := url.Parse(dc.WebHookURL)
+	if err != nil {
+		return "", err
+	}
+	timestamp := strconv.FormatInt(now.UnixNano()/int64(time.Millisecond), 10)
+	mac := hmac.New(sha256.New, []byte(dc.Secret))
+	mac.Write([]byte(timestamp + "\n" + dc.Secret))
+
+	query := u.Query()
+	query.Set("timestamp", timestamp)
+	query.Set("sign", base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
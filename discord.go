@@ -4,9 +4,12 @@ package notify
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/projectdiscovery/retryablehttp-go"
@@ -19,58 +22,230 @@ const DefaultDiscordTimeout = 5 * time.Second
 type DiscordClient struct {
 	client     *retryablehttp.Client
 	WebHookURL string
-	UserName   string
-	Avatar     string
+	// WebHookURLs are used instead of WebHookURL according to WebHookStrategy
+	WebHookURLs     []string
+	WebHookStrategy WebhookStrategy
+	UserName        string
+	Avatar          string
+	// AllowedMentions of regular messages, nil suppresses every ping
+	AllowedMentions *DiscordAllowedMentions
+	// CriticalRoles are role ids pinged by SendError
+	CriticalRoles []string
+	// Severities overrides the colors and labels of the severity helpers
+	Severities SeverityScheme
 	TimeOut    time.Duration
+
+	webhooks webhookPool
 }
 
 // DiscordMessage json structure
 type DiscordMessage struct {
-	Username  string `json:"username,omitempty"`
-	AvatarURL string `json:"avatar_url,omitempty"`
-	Content   string `json:"content,omitempty"`
+	Username        string                  `json:"username,omitempty"`
+	AvatarURL       string                  `json:"avatar_url,omitempty"`
+	Content         string                  `json:"content,omitempty"`
+	AllowedMentions *DiscordAllowedMentions `json:"allowed_mentions,omitempty"`
+	Embeds          []DiscordEmbed          `json:"embeds,omitempty"`
+}
+
+// DiscordEmbed is a rich content block of a message
+type DiscordEmbed struct {
+	Title       string              `json:"title,omitempty"`
+	Description string              `json:"description,omitempty"`
+	URL         string              `json:"url,omitempty"`
+	Color       int                 `json:"color,omitempty"`
+	Timestamp   string              `json:"timestamp,omitempty"`
+	Fields      []DiscordEmbedField `json:"fields,omitempty"`
+	Footer      *DiscordEmbedFooter `json:"footer,omitempty"`
+}
+
+// DiscordEmbedField is a name value pair of an embed
+type DiscordEmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline,omitempty"`
+}
+
+// DiscordEmbedFooter of an embed
+type DiscordEmbedFooter struct {
+	Text string `json:"text"`
+}
+
+// DiscordAllowedMentions controls which mentions in the content notify users
+type DiscordAllowedMentions struct {
+	// Parse lists the mention types allowed: roles, users and everyone
+	Parse []string `json:"parse"`
+	Roles []string `json:"roles,omitempty"`
+	Users []string `json:"users,omitempty"`
 }
 
 // SendInfo to discord
 func (dc *DiscordClient) SendInfo(message string) (err error) {
-	return dc.SendDiscordNotification(&DiscordMessage{
-		Content:   message,
-		Username:  dc.UserName,
-		AvatarURL: dc.Avatar,
-	})
+	return dc.SendInfoCtx(context.Background(), message)
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (dc *DiscordClient) SendInfoCtx(ctx context.Context, message string) error {
+	_, err := dc.sendChunks(ctx, message, dc.Severities.Style(SeverityInfo))
+	return err
+}
+
+// SendInfoResult sends the info message and describes the delivery of its first chunk
+func (dc *DiscordClient) SendInfoResult(ctx context.Context, message string) (*DeliveryResult, error) {
+	return dc.sendChunks(ctx, message, dc.Severities.Style(SeverityInfo))
+}
+
+// SendWarning to discord
+func (dc *DiscordClient) SendWarning(message string) (err error) {
+	return dc.SendWarningCtx(context.Background(), message)
+}
+
+// SendWarningCtx is SendWarning canceled with the context
+func (dc *DiscordClient) SendWarningCtx(ctx context.Context, message string) error {
+	_, err := dc.sendChunks(ctx, message, dc.Severities.Style(SeverityWarning))
+	return err
+}
+
+// sendChunks sends the numbered chunks of a long message as consecutive embeds
+func (dc *DiscordClient) sendChunks(ctx context.Context, message string, style SeverityStyle) (*DeliveryResult, error) {
+	var result *DeliveryResult
+	for i, chunk := range ChunkMessage(message, DiscordMaxMessageLength) {
+		embed := &DiscordEmbed{Description: chunk, Color: style.intColor()}
+		if i == 0 {
+			embed.Title = style.Label
+		}
+		sent, err := dc.sendEmbed(ctx, embed)
+		if err != nil {
+			return nil, err
+		}
+		if result == nil {
+			result = sent
+		}
+	}
+	return result, nil
+}
+
+// SendError to discord pinging the critical roles
+func (dc *DiscordClient) SendError(message string) (err error) {
+	return dc.SendErrorCtx(context.Background(), message)
+}
+
+// SendErrorCtx is SendError canceled with the context
+func (dc *DiscordClient) SendErrorCtx(ctx context.Context, message string) error {
+	var content string
+	if len(dc.CriticalRoles) > 0 {
+		mentions := make([]string, len(dc.CriticalRoles))
+		for i, role := range dc.CriticalRoles {
+			mentions[i] = "<@&" + role + ">"
+		}
+		content = strings.Join(mentions, " ")
+	}
+	style := dc.Severities.Style(SeverityError)
+	chunks := ChunkMessage(message, DiscordMaxMessageLength)
+	if err := dc.SendDiscordNotificationCtx(ctx, &DiscordMessage{
+		Content:         content,
+		Username:        dc.UserName,
+		AvatarURL:       dc.Avatar,
+		AllowedMentions: &DiscordAllowedMentions{Parse: []string{}, Roles: dc.CriticalRoles},
+		Embeds:          []DiscordEmbed{{Title: style.Label, Description: chunks[0], Color: style.intColor()}},
+	}); err != nil {
+		return err
+	}
+	for _, chunk := range chunks[1:] {
+		if _, err := dc.sendEmbed(ctx, &DiscordEmbed{Description: chunk, Color: style.intColor()}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SendEmbed to discord with the client username and avatar
+func (dc *DiscordClient) SendEmbed(embeds ...*DiscordEmbed) error {
+	_, err := dc.sendEmbed(context.Background(), embeds...)
+	return err
+}
+
+func (dc *DiscordClient) sendEmbed(ctx context.Context, embeds ...*DiscordEmbed) (*DeliveryResult, error) {
+	message := &DiscordMessage{
+		Username:        dc.UserName,
+		AvatarURL:       dc.Avatar,
+		AllowedMentions: dc.allowedMentions(),
+	}
+	for _, embed := range embeds {
+		message.Embeds = append(message.Embeds, *embed)
+	}
+	return dc.sendHTTPRequest(ctx, message)
+}
+
+// allowedMentions returns the configured mentions or the no ping default
+func (dc *DiscordClient) allowedMentions() *DiscordAllowedMentions {
+	if dc.AllowedMentions != nil {
+		return dc.AllowedMentions
+	}
+	return &DiscordAllowedMentions{Parse: []string{}}
 }
 
 // SendDiscordNotification with json structure
 func (dc *DiscordClient) SendDiscordNotification(discordMessage *DiscordMessage) error {
-	return dc.sendHTTPRequest(discordMessage)
+	return dc.SendDiscordNotificationCtx(context.Background(), discordMessage)
+}
+
+// SendDiscordNotificationCtx is SendDiscordNotification canceled with the context
+func (dc *DiscordClient) SendDiscordNotificationCtx(ctx context.Context, discordMessage *DiscordMessage) error {
+	_, err := dc.sendHTTPRequest(ctx, discordMessage)
+	return err
 }
 
-func (dc *DiscordClient) sendHTTPRequest(discordMessage *DiscordMessage) error {
+func (dc *DiscordClient) sendHTTPRequest(ctx context.Context, discordMessage *DiscordMessage) (*DeliveryResult, error) {
 	discordBody, err := json.Marshal(discordMessage)
 	if err != nil {
+		return nil, err
+	}
+	var result *DeliveryResult
+	err = dc.webhooks.send(webhookURLs(dc.WebHookURL, dc.WebHookURLs), dc.WebHookStrategy, func(URL string) (err error) {
+		result, err = dc.post(ctx, URL, discordBody)
 		return err
+	})
+	return result, err
+}
+
+// post waits for the created message so its id is returned
+func (dc *DiscordClient) post(ctx context.Context, URL string, discordBody []byte) (*DeliveryResult, error) {
+	waitURL, err := url.Parse(URL)
+	if err != nil {
+		return nil, err
 	}
+	query := waitURL.Query()
+	query.Set("wait", "true")
+	waitURL.RawQuery = query.Encode()
 
-	req, err := retryablehttp.NewRequest(http.MethodPost, dc.WebHookURL, bytes.NewBuffer(discordBody))
+	req, err := retryablehttp.NewRequest(http.MethodPost, waitURL.String(), bytes.NewBuffer(discordBody))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	req.Header.Add("Content-Type", "application/json")
-	resp, err := dc.client.Do(req)
+	resp, err := doTimeout(dc.client, req.WithContext(ctx), dc.TimeOut)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	buf, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	//nolint:errcheck // silent fail
 	defer resp.Body.Close()
 
-	if string(buf) != ok {
-		return err
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, newResponseError(resp, buf)
 	}
 
-	return nil
+	result := newDeliveryResult(ProviderDiscord, resp.StatusCode)
+	var created struct {
+		ID        string `json:"id"`
+		ChannelID string `json:"channel_id"`
+	}
+	if json.Unmarshal(buf, &created) == nil {
+		result.MessageID, result.Channel = created.ID, created.ChannelID
+	}
+	return result, nil
 }
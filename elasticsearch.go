@@ -0,0 +1,203 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// DefaultElasticsearchTimeout to conclude operations
+const DefaultElasticsearchTimeout = 10 * time.Second
+
+// ElasticsearchClient indexes notifications into Elasticsearch or OpenSearch
+type ElasticsearchClient struct {
+	client   *retryablehttp.Client
+	URL      string
+	Index    string
+	Username string
+	Password string
+	APIKey   string
+	// Source identifies the emitter in the source field of the documents
+	Source string
+	// Template installs an index template matching the index when set
+	Template  bool
+	BatchSize int
+	TimeOut   time.Duration
+
+	batch recordBatch
+	// templateInstalled is set once the template install succeeded, failed
+	// installs are retried on the next bulk
+	templateMutex     sync.Mutex
+	templateInstalled bool
+}
+
+// ElasticsearchDocument is the indexed form of a notification
+type ElasticsearchDocument struct {
+	Timestamp time.Time `json:"@timestamp"`
+	Severity  string    `json:"severity"`
+	Source    string    `json:"source,omitempty"`
+	Message   string    `json:"message"`
+}
+
+// elasticsearchBulkResponse is the relevant part of a bulk api answer
+type elasticsearchBulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Status int `json:"status"`
+		Error  struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
+
+// SendInfo indexes the message, with batching enabled documents are sent once the batch is full
+func (ec *ElasticsearchClient) SendInfo(message string) error {
+	return ec.SendInfoCtx(context.Background(), message)
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (ec *ElasticsearchClient) SendInfoCtx(ctx context.Context, message string) error {
+	return ec.send(ctx, "info", message)
+}
+
+// SendWarning indexes the message with the warning severity
+func (ec *ElasticsearchClient) SendWarning(message string) error {
+	return ec.send(context.Background(), "warning", message)
+}
+
+// SendError indexes the message with the error severity
+func (ec *ElasticsearchClient) SendError(message string) error {
+	return ec.send(context.Background(), "error", message)
+}
+
+func (ec *ElasticsearchClient) send(ctx context.Context, severity, message string) error {
+	if records := ec.batch.add(newSeverityRecord(severity, message), ec.BatchSize); len(records) > 0 {
+		return ec.bulk(ctx, records)
+	}
+	return nil
+}
+
+// Flush indexes the pending batched messages
+func (ec *ElasticsearchClient) Flush() error {
+	if records := ec.batch.drain(); len(records) > 0 {
+		return ec.bulk(context.Background(), records)
+	}
+	return nil
+}
+
+func (ec *ElasticsearchClient) bulk(ctx context.Context, records []record) error {
+	if err := ec.installTemplate(ctx); err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, r := range records {
+		if err := enc.Encode(map[string]interface{}{"index": map[string]string{"_index": ec.Index}}); err != nil {
+			return err
+		}
+		document := ElasticsearchDocument{Timestamp: r.Time, Severity: r.Severity, Source: ec.Source, Message: r.Message}
+		if err := enc.Encode(document); err != nil {
+			return err
+		}
+	}
+
+	buf, err := ec.do(ctx, http.MethodPost, "/_bulk", "application/x-ndjson", body.Bytes())
+	if err != nil {
+		return err
+	}
+	var bulkResponse elasticsearchBulkResponse
+	if err := json.Unmarshal(buf, &bulkResponse); err != nil {
+		return err
+	}
+	if bulkResponse.Errors {
+		for _, item := range bulkResponse.Items {
+			for _, result := range item {
+				if result.Error.Type != "" {
+					return fmt.Errorf("elasticsearch bulk index failed: %s: %s", result.Error.Type, result.Error.Reason)
+				}
+			}
+		}
+		return fmt.Errorf("elasticsearch bulk index failed")
+	}
+	return nil
+}
+
+// installTemplate puts the index template unless it is already installed
+func (ec *ElasticsearchClient) installTemplate(ctx context.Context) error {
+	if !ec.Template {
+		return nil
+	}
+	ec.templateMutex.Lock()
+	defer ec.templateMutex.Unlock()
+
+	if ec.templateInstalled {
+		return nil
+	}
+	if err := ec.putTemplate(ctx); err != nil {
+		return err
+	}
+	ec.templateInstalled = true
+	return nil
+}
+
+// putTemplate installs an index template mapping the notification fields
+func (ec *ElasticsearchClient) putTemplate(ctx context.Context) error {
+	template := map[string]interface{}{
+		"index_patterns": []string{ec.Index + "*"},
+		"template": map[string]interface{}{
+			"mappings": map[string]interface{}{
+				"properties": map[string]interface{}{
+					"@timestamp": map[string]string{"type": "date"},
+					"severity":   map[string]string{"type": "keyword"},
+					"source":     map[string]string{"type": "keyword"},
+					"message":    map[string]string{"type": "text"},
+				},
+			},
+		},
+	}
+	body, err := json.Marshal(template)
+	if err != nil {
+		return err
+	}
+	_, err = ec.do(ctx, http.MethodPut, "/_index_template/"+ec.Index, "application/json", body)
+	return err
+}
+
+func (ec *ElasticsearchClient) do(ctx context.Context, method, path, contentType string, body []byte) ([]byte, error) {
+	req, err := retryablehttp.NewRequest(method, strings.TrimSuffix(ec.URL, "/")+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if ec.APIKey != "" {
+		req.Header.Set("Authorization", "ApiKey "+ec.APIKey)
+	} else if ec.Username != "" {
+		req.SetBasicAuth(ec.Username, ec.Password)
+	}
+
+	resp, err := doTimeout(ec.client, req.WithContext(ctx), ec.TimeOut)
+	if err != nil {
+		return nil, err
+	}
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	//nolint:errcheck // silent fail
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, newResponseError(resp, buf)
+	}
+	return buf, nil
+}
@@ -0,0 +1,301 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	htmltemplate "html/template"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// DefaultEmailTimeout to conclude operations
+const DefaultEmailTimeout = 10 * time.Second
+
+// DefaultEmailSubject is the subject template used when none is configured
+const DefaultEmailSubject = "[notify] {{.Severity}}: {{.Summary}}"
+
+// EmailTLSMode is the transport security of the smtp connection
+type EmailTLSMode string
+
+// Email tls modes
+const (
+	// EmailSTARTTLS upgrades the plain connection, it's the default
+	EmailSTARTTLS EmailTLSMode = "starttls"
+	// EmailTLS connects with implicit tls, usually on port 465
+	EmailTLS EmailTLSMode = "tls"
+	// EmailNoTLS sends in clear text, for local relays only
+	EmailNoTLS EmailTLSMode = "none"
+)
+
+// ErrSTARTTLSUnsupported is returned when the server doesn't offer STARTTLS
+var ErrSTARTTLSUnsupported = errors.New("smtp server does not support STARTTLS")
+
+// EmailClient sends notifications by mail through a smtp server
+type EmailClient struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	To       []string
+	TLS      EmailTLSMode
+	// SubjectTemplate is a text/template rendered with EmailSubjectData
+	SubjectTemplate string
+	// HTML adds an html alternative of the messages
+	HTML bool
+	// HTMLTemplate is an html/template rendered with EmailSubjectData as the
+	// html alternative, the escaped message is used if empty
+	HTMLTemplate string
+	TimeOut      time.Duration
+
+	capture captureFunc
+}
+
+// EmailSubjectData are the fields available to the subject template
+type EmailSubjectData struct {
+	Severity string
+	// Summary is the first line of the message
+	Summary string
+	Message string
+	Time    time.Time
+}
+
+// EmailMessage structure
+type EmailMessage struct {
+	Subject string
+	Text    string
+	// HTML is the optional html alternative of the text
+	HTML string
+}
+
+// SendInfo by mail
+func (ec *EmailClient) SendInfo(message string) error {
+	return ec.SendInfoCtx(context.Background(), message)
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (ec *EmailClient) SendInfoCtx(ctx context.Context, message string) error {
+	return ec.send(ctx, "info", message)
+}
+
+// SendWarning by mail
+func (ec *EmailClient) SendWarning(message string) error {
+	return ec.send(context.Background(), "warning", message)
+}
+
+// SendError by mail
+func (ec *EmailClient) SendError(message string) error {
+	return ec.send(context.Background(), "error", message)
+}
+
+func (ec *EmailClient) send(ctx context.Context, severity, message string) error {
+	subject, err := ec.subject(severity, message)
+	if err != nil {
+		return err
+	}
+	emailMessage := &EmailMessage{Subject: subject, Text: message}
+	switch {
+	case ec.HTMLTemplate != "":
+		if emailMessage.HTML, err = renderEmailHTML(ec.HTMLTemplate, severity, message); err != nil {
+			return err
+		}
+	case ec.HTML:
+		emailMessage.HTML = "<pre>" + html.EscapeString(message) + "</pre>"
+	}
+	return ec.SendEmailCtx(ctx, emailMessage)
+}
+
+func (ec *EmailClient) subject(severity, message string) (string, error) {
+	return renderEmailSubject(ec.SubjectTemplate, severity, message)
+}
+
+// renderEmailSubject executes the subject template, DefaultEmailSubject if empty
+func renderEmailSubject(text, severity, message string) (string, error) {
+	if text == "" {
+		text = DefaultEmailSubject
+	}
+	tpl, err := template.New("subject").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	summary := message
+	if i := strings.IndexByte(summary, '\n'); i >= 0 {
+		summary = summary[:i]
+	}
+	var subject strings.Builder
+	err = tpl.Execute(&subject, &EmailSubjectData{Severity: severity, Summary: summary, Message: message, Time: time.Now()})
+	return subject.String(), err
+}
+
+// renderEmailHTML executes the html template with the message fields
+func renderEmailHTML(text, severity, message string) (string, error) {
+	tpl, err := htmltemplate.New("html").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var body bytes.Buffer
+	err = tpl.Execute(&body, &EmailSubjectData{Severity: severity, Summary: issueSummary(message, 200), Message: message, Time: time.Now()})
+	return body.String(), err
+}
+
+// SendEmail to the recipients
+func (ec *EmailClient) SendEmail(emailMessage *EmailMessage) error {
+	return ec.SendEmailCtx(context.Background(), emailMessage)
+}
+
+// SendEmailCtx is SendEmail canceled with the context
+func (ec *EmailClient) SendEmailCtx(ctx context.Context, emailMessage *EmailMessage) error {
+	body, err := ec.buildMessage(emailMessage)
+	if err != nil {
+		return err
+	}
+
+	timeout := ec.TimeOut
+	if timeout == 0 {
+		timeout = DefaultEmailTimeout
+	}
+	port := ec.Port
+	if port == 0 {
+		port = 587
+		if ec.TLS == EmailTLS {
+			port = 465
+		}
+	}
+	address := net.JoinHostPort(ec.Host, strconv.Itoa(port))
+	if ec.capture != nil {
+		ec.capture("smtp://"+address, body)
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	tlsConfig := &tls.Config{ServerName: ec.Host, MinVersion: tls.VersionTLS12}
+
+	deadline := socketDeadline(ctx, timeout)
+	dialer := &net.Dialer{Timeout: timeout, Deadline: deadline}
+	var conn net.Conn
+	if ec.TLS == EmailTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", address, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", address)
+	}
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck // bounds the whole smtp exchange
+	conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, ec.Host)
+	if err != nil {
+		//nolint:errcheck // silent fail
+		conn.Close()
+		return err
+	}
+	//nolint:errcheck // silent fail
+	defer client.Close()
+
+	if ec.TLS == "" || ec.TLS == EmailSTARTTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return ErrSTARTTLSUnsupported
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+	if ec.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", ec.Username, ec.Password, ec.Host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(ec.From); err != nil {
+		return err
+	}
+	for _, to := range ec.To {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(body); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// buildMessage returns the rfc 5322 message, multipart when there is an html alternative
+func (ec *EmailClient) buildMessage(emailMessage *EmailMessage) ([]byte, error) {
+	var buf bytes.Buffer
+	header := func(name, value string) {
+		buf.WriteString(name + ": " + value + "\r\n")
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	domain := ec.Host
+	if i := strings.LastIndex(ec.From, "@"); i >= 0 {
+		domain = ec.From[i+1:]
+	}
+
+	header("From", ec.From)
+	header("To", strings.Join(ec.To, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", emailMessage.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("Message-ID", "<"+hex.EncodeToString(id)+"@"+domain+">")
+	header("MIME-Version", "1.0")
+
+	if emailMessage.HTML == "" {
+		header("Content-Type", "text/plain; charset=utf-8")
+		header("Content-Transfer-Encoding", "quoted-printable")
+		buf.WriteString("\r\n")
+		if err := writeQuotedPrintable(&buf, emailMessage.Text); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	boundary := hex.EncodeToString(id)
+	header("Content-Type", fmt.Sprintf("multipart/alternative; boundary=%q", boundary))
+	buf.WriteString("\r\n")
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain", emailMessage.Text},
+		{"text/html", emailMessage.HTML},
+	} {
+		buf.WriteString("--" + boundary + "\r\n")
+		header("Content-Type", part.contentType+"; charset=utf-8")
+		header("Content-Transfer-Encoding", "quoted-printable")
+		buf.WriteString("\r\n")
+		if err := writeQuotedPrintable(&buf, part.body); err != nil {
+			return nil, err
+		}
+		buf.WriteString("\r\n")
+	}
+	buf.WriteString("--" + boundary + "--\r\n")
+	return buf.Bytes(), nil
+}
+
+func writeQuotedPrintable(buf *bytes.Buffer, text string) error {
+	w := quotedprintable.NewWriter(buf)
+	if _, err := w.Write([]byte(strings.ReplaceAll(text, "\n", "\r\n"))); err != nil {
+		return err
+	}
+	return w.Close()
+}
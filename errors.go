@@ -0,0 +1,208 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// Errors reported by providers, use errors.Is to check the kind of a delivery error
+var (
+	ErrRateLimited         = errors.New("rate limited")
+	ErrUnauthorized        = errors.New("unauthorized")
+	ErrPayloadTooLarge     = errors.New("payload too large")
+	ErrProviderUnavailable = errors.New("provider unavailable")
+)
+
+// ProviderError is a delivery failure of a provider
+type ProviderError struct {
+	Provider string
+	// StatusCode of the http response, 0 if none was received
+	StatusCode int
+	// Kind is one of the exported sentinel errors, nil if unclassified
+	Kind error
+	// RetryAfter is the delay requested by the provider before retrying
+	RetryAfter time.Duration
+	Err        error
+}
+
+// Error returns the provider, status and cause of the failure
+func (e *ProviderError) Error() string {
+	var builder strings.Builder
+	if e.Provider != "" {
+		builder.WriteString(e.Provider + ": ")
+	}
+	if e.StatusCode != 0 {
+		builder.WriteString("status " + strconv.Itoa(e.StatusCode) + ": ")
+	}
+	if e.Err != nil {
+		builder.WriteString(e.Err.Error())
+	} else if e.Kind != nil {
+		builder.WriteString(e.Kind.Error())
+	}
+	return builder.String()
+}
+
+// Unwrap returns the underlying error
+func (e *ProviderError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the error is of the target kind
+func (e *ProviderError) Is(target error) bool {
+	return e.Kind != nil && e.Kind == target
+}
+
+// statusKind classifies an http status code
+func statusKind(statusCode int) error {
+	switch {
+	case statusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		return ErrUnauthorized
+	case statusCode == http.StatusRequestEntityTooLarge:
+		return ErrPayloadTooLarge
+	case statusCode >= http.StatusInternalServerError:
+		return ErrProviderUnavailable
+	default:
+		return nil
+	}
+}
+
+// newResponseError returns the error of an unsuccessful http response
+func newResponseError(resp *http.Response, body []byte) error {
+	err := newStatusError(resp.StatusCode, body)
+	err.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+	return err
+}
+
+// parseRetryAfter supports both delay in seconds and http date values
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(at); delay > 0 {
+			return delay
+		}
+	}
+	return 0
+}
+
+// newStatusError returns the error of an unsuccessful status code
+func newStatusError(statusCode int, body []byte) *ProviderError {
+	kind := statusKind(statusCode)
+	err := kind
+	if text := strings.TrimSpace(string(body)); text != "" {
+		err = errors.New(text)
+	}
+	if err == nil {
+		err = errors.New(http.StatusText(statusCode))
+	}
+	return &ProviderError{StatusCode: statusCode, Kind: kind, Err: err}
+}
+
+// exhaustedRetries handles the last response of a request retried until giving up
+func exhaustedRetries(resp *http.Response, err error, numTries int) (*http.Response, error) {
+	statusCode := 0
+	var retryAfter time.Duration
+	if resp != nil {
+		statusCode = resp.StatusCode
+		retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+		//nolint:errcheck // silent fail
+		resp.Body.Close()
+	}
+	kind := statusKind(statusCode)
+	if kind == nil {
+		kind = ErrProviderUnavailable
+	}
+	if err == nil {
+		err = errors.New("giving up after " + strconv.Itoa(numTries) + " attempts")
+	}
+	return nil, &ProviderError{StatusCode: statusCode, Kind: kind, RetryAfter: retryAfter, Err: err}
+}
+
+// maxRetryAfterWait is the longest Retry-After delay waited before retrying,
+// longer ones are reported to the caller
+const maxRetryAfterWait = time.Minute
+
+// retryRateLimits retries the 429 responses of the client after the delay
+// requested in their Retry-After header, falling back to the client policy
+func retryRateLimits(client *retryablehttp.Client) *retryablehttp.Client {
+	checkRetry := client.CheckRetry
+	if checkRetry == nil {
+		checkRetry = retryablehttp.DefaultRetryPolicy()
+	}
+	backoff := client.Backoff
+	if backoff == nil {
+		backoff = retryablehttp.DefaultBackoff()
+	}
+	client.CheckRetry = func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+		if err == nil && resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+			if ctx.Err() != nil {
+				return false, ctx.Err()
+			}
+			return parseRetryAfter(resp.Header.Get("Retry-After")) <= maxRetryAfterWait, nil
+		}
+		return checkRetry(ctx, resp, err)
+	}
+	client.Backoff = func(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
+		if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+			if delay := parseRetryAfter(resp.Header.Get("Retry-After")); delay > 0 {
+				return delay
+			}
+		}
+		return backoff(min, max, attemptNum, resp)
+	}
+	return client
+}
+
+// IsRetryable reports whether delivering again may succeed: rate limits,
+// unavailable providers, timeouts and network errors are retryable
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, ErrRateLimited) || errors.Is(err, ErrProviderUnavailable) {
+		return true
+	}
+	var providerErr *ProviderError
+	if errors.As(err, &providerErr) && providerErr.StatusCode == http.StatusRequestTimeout {
+		return true
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// RetryAfter returns the delay requested by the provider before retrying, 0 if unknown
+func RetryAfter(err error) time.Duration {
+	var providerErr *ProviderError
+	if errors.As(err, &providerErr) {
+		return providerErr.RetryAfter
+	}
+	return 0
+}
+
+// withProvider attributes the error to the provider
+func withProvider(provider string, err error) error {
+	var providerErr *ProviderError
+	if errors.As(err, &providerErr) {
+		if providerErr.Provider == "" {
+			providerErr.Provider = provider
+		}
+		return err
+	}
+	return &ProviderError{Provider: provider, Err: err}
+}
@@ -0,0 +1,87 @@
+package notify
+
+import (
+	"sync"
+	"time"
+)
+
+// EventType is the kind of a delivery event
+type EventType string
+
+// Delivery event types
+const (
+	EventEnqueued     EventType = "enqueued"
+	EventSent         EventType = "sent"
+	EventRetried      EventType = "retried"
+	EventFailed       EventType = "failed"
+	EventDropped      EventType = "dropped"
+	EventDeadLettered EventType = "dead-lettered"
+	// EventCircuitOpened is published when failures open the circuit of a provider
+	EventCircuitOpened EventType = "circuit-opened"
+)
+
+// DefaultEventBuffer is the channel capacity of a subscription
+const DefaultEventBuffer = 100
+
+// Event describes the outcome of a notification
+type Event struct {
+	Type     EventType
+	Provider string
+	Message  string
+	Attempt  int
+	// Result of sent events, message ids are set on the providers returning them
+	Result *DeliveryResult
+	Error  error
+	Time   time.Time
+}
+
+// eventBus fans out events to subscribers without blocking deliveries
+type eventBus struct {
+	sync.RWMutex
+	subscribers map[chan Event]struct{}
+}
+
+func newEventBus() *eventBus {
+	return &eventBus{subscribers: make(map[chan Event]struct{})}
+}
+
+func (b *eventBus) publish(event *Event) {
+	b.RLock()
+	defer b.RUnlock()
+
+	if len(b.subscribers) == 0 {
+		return
+	}
+	event.Time = time.Now()
+	for subscriber := range b.subscribers {
+		// slow subscribers miss events rather than stalling the senders
+		select {
+		case subscriber <- *event:
+		default:
+		}
+	}
+}
+
+// Subscribe returns a channel receiving delivery events and a function
+// to cancel the subscription. Events are discarded if the channel is full.
+func (n *Notify) Subscribe(buffer int) (events <-chan Event, cancel func()) {
+	if buffer <= 0 {
+		buffer = DefaultEventBuffer
+	}
+	subscriber := make(chan Event, buffer)
+
+	n.events.Lock()
+	n.events.subscribers[subscriber] = struct{}{}
+	n.events.Unlock()
+
+	var once sync.Once
+	cancel = func() {
+		once.Do(func() {
+			n.events.Lock()
+			delete(n.events.subscribers, subscriber)
+			n.events.Unlock()
+			close(subscriber)
+		})
+	}
+	return subscriber, cancel
+}
@@ -0,0 +1,135 @@
+package notify
+
+import "sync"
+
+const (
+	// healthWindow is the number of recent deliveries used to compute success rates
+	healthWindow = 20
+	// healthMinSamples is the number of deliveries needed before judging a provider
+	healthMinSamples = 5
+	// healthUnhealthyBelow marks a provider as flapping when its success rate drops below
+	healthUnhealthyBelow = 0.5
+	// healthHealthyAbove restores a flapping provider once its success rate recovers above
+	healthHealthyAbove = 0.8
+)
+
+// providerHealth is the rolling delivery outcome of a provider
+type providerHealth struct {
+	outcomes  [healthWindow]bool
+	next      int
+	samples   int
+	unhealthy bool
+}
+
+func (h *providerHealth) successRate() float64 {
+	if h.samples == 0 {
+		return 1
+	}
+	var success int
+	for i := 0; i < h.samples; i++ {
+		if h.outcomes[i] {
+			success++
+		}
+	}
+	return float64(success) / float64(h.samples)
+}
+
+// healthTracker tracks success rates, the distinct thresholds to enter and
+// leave the unhealthy state avoid ping-ponging between providers
+type healthTracker struct {
+	sync.Mutex
+	providers map[string]*providerHealth
+}
+
+func newHealthTracker() *healthTracker {
+	return &healthTracker{providers: make(map[string]*providerHealth)}
+}
+
+func (t *healthTracker) record(provider string, success bool) {
+	t.Lock()
+	defer t.Unlock()
+
+	health, ok := t.providers[provider]
+	if !ok {
+		health = &providerHealth{}
+		t.providers[provider] = health
+	}
+	health.outcomes[health.next] = success
+	health.next = (health.next + 1) % healthWindow
+	if health.samples < healthWindow {
+		health.samples++
+	}
+	if health.samples < healthMinSamples {
+		return
+	}
+	rate := health.successRate()
+	if health.unhealthy && rate > healthHealthyAbove {
+		health.unhealthy = false
+	} else if !health.unhealthy && rate < healthUnhealthyBelow {
+		health.unhealthy = true
+	}
+}
+
+func (t *healthTracker) healthy(provider string) bool {
+	t.Lock()
+	defer t.Unlock()
+
+	health, ok := t.providers[provider]
+	return !ok || !health.unhealthy
+}
+
+// order returns the healthy providers first, keeping the configured priority
+func (t *healthTracker) order(providers []provider) []provider {
+	ordered := make([]provider, 0, len(providers))
+	var unhealthy []provider
+	for _, p := range providers {
+		if t.healthy(p.name) {
+			ordered = append(ordered, p)
+		} else {
+			unhealthy = append(unhealthy, p)
+		}
+	}
+	// flapping providers are still tried as last resort
+	return append(ordered, unhealthy...)
+}
+
+// deliveryGroups splits the enabled providers in groups receiving the message
+// once, in fan-out each provider is a group on its own
+func (n *Notify) deliveryGroups() [][]provider {
+	if n.options == nil {
+		return nil
+	}
+	providers := n.enabledProviders()
+	byName := make(map[string]provider, len(providers))
+	for _, p := range providers {
+		byName[p.name] = p
+	}
+	groupOf := make(map[string]int)
+	for i, group := range n.options.FailoverGroups {
+		for _, name := range group {
+			groupOf[name] = i
+		}
+	}
+
+	var groups [][]provider
+	emitted := make(map[int]bool)
+	for _, p := range providers {
+		index, grouped := groupOf[p.name]
+		if !grouped {
+			groups = append(groups, []provider{p})
+			continue
+		}
+		if emitted[index] {
+			continue
+		}
+		emitted[index] = true
+		var group []provider
+		for _, name := range n.options.FailoverGroups[index] {
+			if member, ok := byName[name]; ok {
+				group = append(group, member)
+			}
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
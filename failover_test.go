@@ -0,0 +1,81 @@
+package notify
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHealthTrackerHysteresis(t *testing.T) {
+	tracker := newHealthTracker()
+	record := func(success bool, times int) {
+		for i := 0; i < times; i++ {
+			tracker.record("slack", success)
+		}
+	}
+
+	record(false, healthMinSamples-1)
+	if !tracker.healthy("slack") {
+		t.Fatal("provider judged before the minimum samples")
+	}
+	record(false, 1)
+	if tracker.healthy("slack") {
+		t.Fatal("provider healthy after failing every delivery")
+	}
+	// 16 successes leave 4 failures in the window, a success rate of 0.8
+	record(true, 16)
+	if tracker.healthy("slack") {
+		t.Fatal("provider restored at the unhealthy threshold")
+	}
+	record(true, 1)
+	if !tracker.healthy("slack") {
+		t.Fatal("provider not restored above the healthy threshold")
+	}
+	// 9 failures in the window, a success rate of 0.55
+	record(false, 9)
+	if !tracker.healthy("slack") {
+		t.Fatal("provider flagged above the unhealthy threshold")
+	}
+	record(false, 2)
+	if tracker.healthy("slack") {
+		t.Fatal("provider not flagged below the unhealthy threshold")
+	}
+}
+
+func TestHealthTrackerOrder(t *testing.T) {
+	tracker := newHealthTracker()
+	for i := 0; i < healthMinSamples; i++ {
+		tracker.record(ProviderSlack, false)
+		tracker.record(ProviderDiscord, true)
+	}
+	providers := []provider{{name: ProviderSlack}, {name: ProviderTelegram}, {name: ProviderDiscord}}
+
+	var names []string
+	for _, p := range tracker.order(providers) {
+		names = append(names, p.name)
+	}
+	if want := []string{ProviderTelegram, ProviderDiscord, ProviderSlack}; !reflect.DeepEqual(names, want) {
+		t.Errorf("order() = %v, want %v", names, want)
+	}
+}
+
+func TestDeliveryGroups(t *testing.T) {
+	n := &Notify{options: &Options{
+		Slack:          true,
+		Discord:        true,
+		Telegram:       true,
+		FailoverGroups: [][]string{{ProviderTelegram, ProviderSlack, "disabled"}},
+	}}
+
+	var groups [][]string
+	for _, group := range n.deliveryGroups() {
+		var names []string
+		for _, p := range group {
+			names = append(names, p.name)
+		}
+		groups = append(groups, names)
+	}
+	want := [][]string{{ProviderTelegram, ProviderSlack}, {ProviderDiscord}}
+	if !reflect.DeepEqual(groups, want) {
+		t.Errorf("deliveryGroups() = %v, want %v", groups, want)
+	}
+}
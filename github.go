@@ -0,0 +1,160 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// DefaultGitHubTimeout to conclude operations
+const DefaultGitHubTimeout = 10 * time.Second
+
+// DefaultGitHubURL is the api of github.com, enterprise servers use https://host/api/v3
+const DefaultGitHubURL = "https://api.github.com"
+
+// githubMaxTitle bounds the issue titles
+const githubMaxTitle = 256
+
+// GitHubClient opens issues in a repository
+type GitHubClient struct {
+	client *retryablehttp.Client
+	// URL of the api, DefaultGitHubURL if empty
+	URL   string
+	Token string
+	// Repository as owner/name
+	Repository string
+	// SeverityLabels maps info, warning and error to the label of their issues
+	SeverityLabels map[string]string
+	// Correlate comments the open issue of an identical title instead of opening a new one
+	Correlate bool
+	TimeOut   time.Duration
+}
+
+// GitHubIssue is a created or matched issue
+type GitHubIssue struct {
+	Number  int    `json:"number"`
+	HTMLURL string `json:"html_url"`
+}
+
+// SendInfo to github
+func (gc *GitHubClient) SendInfo(message string) error {
+	return gc.SendInfoCtx(context.Background(), message)
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (gc *GitHubClient) SendInfoCtx(ctx context.Context, message string) error {
+	_, err := gc.SendIssueCtx(ctx, message, gc.SeverityLabels["info"])
+	return err
+}
+
+// SendWarning to github
+func (gc *GitHubClient) SendWarning(message string) error {
+	_, err := gc.SendIssueCtx(context.Background(), message, gc.SeverityLabels["warning"])
+	return err
+}
+
+// SendError to github
+func (gc *GitHubClient) SendError(message string) error {
+	_, err := gc.SendIssueCtx(context.Background(), message, gc.SeverityLabels["error"])
+	return err
+}
+
+// SendIssue opens an issue with the message, or comments the correlated
+// open issue, returning the issue
+func (gc *GitHubClient) SendIssue(message string, labels ...string) (*GitHubIssue, error) {
+	return gc.SendIssueCtx(context.Background(), message, labels...)
+}
+
+// SendIssueCtx is SendIssue canceled with the context
+func (gc *GitHubClient) SendIssueCtx(ctx context.Context, message string, labels ...string) (*GitHubIssue, error) {
+	title := issueSummary(message, githubMaxTitle)
+	var issueLabels []string
+	for _, label := range labels {
+		if label != "" {
+			issueLabels = append(issueLabels, label)
+		}
+	}
+	if gc.Correlate {
+		label := correlationLabel(title)
+		issueLabels = append(issueLabels, label)
+		var issues []GitHubIssue
+		query := url.Values{"labels": {label}, "state": {"open"}, "per_page": {"1"}}
+		if err := gc.do(ctx, http.MethodGet, "/issues?"+query.Encode(), nil, &issues); err != nil {
+			return nil, err
+		}
+		if len(issues) > 0 {
+			return &issues[0], gc.AddCommentCtx(ctx, issues[0].Number, message)
+		}
+	}
+
+	payload := map[string]interface{}{"title": title, "body": message}
+	if len(issueLabels) > 0 {
+		payload["labels"] = issueLabels
+	}
+	var issue GitHubIssue
+	if err := gc.do(ctx, http.MethodPost, "/issues", payload, &issue); err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// AddComment to the issue
+func (gc *GitHubClient) AddComment(number int, comment string) error {
+	return gc.AddCommentCtx(context.Background(), number, comment)
+}
+
+// AddCommentCtx is AddComment canceled with the context
+func (gc *GitHubClient) AddCommentCtx(ctx context.Context, number int, comment string) error {
+	return gc.do(ctx, http.MethodPost, "/issues/"+strconv.Itoa(number)+"/comments", map[string]string{"body": comment}, nil)
+}
+
+// do calls the api relative to the repository
+func (gc *GitHubClient) do(ctx context.Context, method, path string, payload, result interface{}) error {
+	var body interface{}
+	if payload != nil {
+		buf, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = buf
+	}
+	server := gc.URL
+	if server == "" {
+		server = DefaultGitHubURL
+	}
+	req, err := retryablehttp.NewRequest(method, strings.TrimSuffix(server, "/")+"/repos/"+gc.Repository+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Accept", "application/vnd.github+json")
+	req.Header.Add("Authorization", "Bearer "+gc.Token)
+	if payload != nil {
+		req.Header.Add("Content-Type", "application/json")
+	}
+
+	resp, err := doTimeout(gc.client, req.WithContext(ctx), gc.TimeOut)
+	if err != nil {
+		return err
+	}
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck // silent fail
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return newResponseError(resp, buf)
+	}
+	if result != nil {
+		return json.Unmarshal(buf, result)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// DefaultGitLabTimeout to conclude operations
+const DefaultGitLabTimeout = 10 * time.Second
+
+// DefaultGitLabURL is gitlab.com, self-hosted instances use their base url
+const DefaultGitLabURL = "https://gitlab.com"
+
+// gitlabMaxTitle bounds the issue titles
+const gitlabMaxTitle = 255
+
+// GitLabClient opens issues in a project
+type GitLabClient struct {
+	client *retryablehttp.Client
+	// URL of the instance, DefaultGitLabURL if empty
+	URL string
+	// Token is a personal, group or project access token with the api scope
+	Token string
+	// Project is the numeric id or the path as group/project
+	Project string
+	// SeverityLabels maps info, warning and error to the label of their issues
+	SeverityLabels map[string]string
+	// Correlate adds a note to the open issue of an identical title instead of opening a new one
+	Correlate bool
+	TimeOut   time.Duration
+}
+
+// GitLabIssue is a created or matched issue
+type GitLabIssue struct {
+	IID    int    `json:"iid"`
+	WebURL string `json:"web_url"`
+}
+
+// SendInfo to gitlab
+func (gc *GitLabClient) SendInfo(message string) error {
+	return gc.SendInfoCtx(context.Background(), message)
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (gc *GitLabClient) SendInfoCtx(ctx context.Context, message string) error {
+	_, err := gc.SendIssueCtx(ctx, message, gc.SeverityLabels["info"])
+	return err
+}
+
+// SendWarning to gitlab
+func (gc *GitLabClient) SendWarning(message string) error {
+	_, err := gc.SendIssueCtx(context.Background(), message, gc.SeverityLabels["warning"])
+	return err
+}
+
+// SendError to gitlab
+func (gc *GitLabClient) SendError(message string) error {
+	_, err := gc.SendIssueCtx(context.Background(), message, gc.SeverityLabels["error"])
+	return err
+}
+
+// SendIssue opens an issue with the message, or adds a note to the
+// correlated open issue, returning the issue
+func (gc *GitLabClient) SendIssue(message string, labels ...string) (*GitLabIssue, error) {
+	return gc.SendIssueCtx(context.Background(), message, labels...)
+}
+
+// SendIssueCtx is SendIssue canceled with the context
+func (gc *GitLabClient) SendIssueCtx(ctx context.Context, message string, labels ...string) (*GitLabIssue, error) {
+	title := issueSummary(message, gitlabMaxTitle)
+	var issueLabels []string
+	for _, label := range labels {
+		if label != "" {
+			issueLabels = append(issueLabels, label)
+		}
+	}
+	if gc.Correlate {
+		label := correlationLabel(title)
+		issueLabels = append(issueLabels, label)
+		var issues []GitLabIssue
+		query := url.Values{"labels": {label}, "state": {"opened"}, "per_page": {"1"}}
+		if err := gc.do(ctx, http.MethodGet, "/issues?"+query.Encode(), nil, &issues); err != nil {
+			return nil, err
+		}
+		if len(issues) > 0 {
+			return &issues[0], gc.AddNoteCtx(ctx, issues[0].IID, message)
+		}
+	}
+
+	payload := map[string]string{"title": title, "description": message}
+	if len(issueLabels) > 0 {
+		payload["labels"] = strings.Join(issueLabels, ",")
+	}
+	var issue GitLabIssue
+	if err := gc.do(ctx, http.MethodPost, "/issues", payload, &issue); err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// AddNote to the issue
+func (gc *GitLabClient) AddNote(iid int, note string) error {
+	return gc.AddNoteCtx(context.Background(), iid, note)
+}
+
+// AddNoteCtx is AddNote canceled with the context
+func (gc *GitLabClient) AddNoteCtx(ctx context.Context, iid int, note string) error {
+	return gc.do(ctx, http.MethodPost, "/issues/"+strconv.Itoa(iid)+"/notes", map[string]string{"body": note}, nil)
+}
+
+// do calls the api relative to the project
+func (gc *GitLabClient) do(ctx context.Context, method, path string, payload, result interface{}) error {
+	var body interface{}
+	if payload != nil {
+		buf, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = buf
+	}
+	server := gc.URL
+	if server == "" {
+		server = DefaultGitLabURL
+	}
+	URL := strings.TrimSuffix(server, "/") + "/api/v4/projects/" + url.PathEscape(gc.Project) + path
+	req, err := retryablehttp.NewRequest(method, URL, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("PRIVATE-TOKEN", gc.Token)
+	if payload != nil {
+		req.Header.Add("Content-Type", "application/json")
+	}
+
+	resp, err := doTimeout(gc.client, req.WithContext(ctx), gc.TimeOut)
+	if err != nil {
+		return err
+	}
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck // silent fail
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return newResponseError(resp, buf)
+	}
+	if result != nil {
+		return json.Unmarshal(buf, result)
+	}
+	return nil
+}
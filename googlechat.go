@@ -0,0 +1,145 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// DefaultGoogleChatTimeout to conclude operations
+const DefaultGoogleChatTimeout = 5 * time.Second
+
+// GoogleChatClient handling google chat space webhooks
+type GoogleChatClient struct {
+	client     *retryablehttp.Client
+	WebHookURL string
+	// ThreadKey groups the messages in a single thread of the space
+	ThreadKey string
+	TimeOut   time.Duration
+}
+
+// GoogleChatMessage json structure
+type GoogleChatMessage struct {
+	Text    string           `json:"text,omitempty"`
+	CardsV2 []GoogleChatCard `json:"cardsV2,omitempty"`
+}
+
+// GoogleChatCard wraps a card in a message
+type GoogleChatCard struct {
+	CardID string             `json:"cardId"`
+	Card   GoogleChatCardBody `json:"card"`
+}
+
+// GoogleChatCardBody is the content of a card
+type GoogleChatCardBody struct {
+	Header   *GoogleChatCardHeader `json:"header,omitempty"`
+	Sections []GoogleChatSection   `json:"sections"`
+}
+
+// GoogleChatCardHeader of a card
+type GoogleChatCardHeader struct {
+	Title    string `json:"title"`
+	Subtitle string `json:"subtitle,omitempty"`
+	ImageURL string `json:"imageUrl,omitempty"`
+}
+
+// GoogleChatSection groups widgets of a card
+type GoogleChatSection struct {
+	Header  string             `json:"header,omitempty"`
+	Widgets []GoogleChatWidget `json:"widgets"`
+}
+
+// GoogleChatWidget is a text paragraph or a labeled text
+type GoogleChatWidget struct {
+	TextParagraph *GoogleChatTextParagraph `json:"textParagraph,omitempty"`
+	DecoratedText *GoogleChatDecoratedText `json:"decoratedText,omitempty"`
+}
+
+// GoogleChatTextParagraph widget
+type GoogleChatTextParagraph struct {
+	Text string `json:"text"`
+}
+
+// GoogleChatDecoratedText widget
+type GoogleChatDecoratedText struct {
+	TopLabel string `json:"topLabel,omitempty"`
+	Text     string `json:"text"`
+}
+
+// NewGoogleChatCard returns a card with the title and the text as paragraph
+func NewGoogleChatCard(title, text string) GoogleChatCard {
+	return GoogleChatCard{
+		CardID: "notify",
+		Card: GoogleChatCardBody{
+			Header:   &GoogleChatCardHeader{Title: title},
+			Sections: []GoogleChatSection{{Widgets: []GoogleChatWidget{{TextParagraph: &GoogleChatTextParagraph{Text: text}}}}},
+		},
+	}
+}
+
+// SendInfo to google chat
+func (gc *GoogleChatClient) SendInfo(message string) error {
+	return gc.SendInfoCtx(context.Background(), message)
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (gc *GoogleChatClient) SendInfoCtx(ctx context.Context, message string) error {
+	return gc.SendGoogleChatNotificationCtx(ctx, &GoogleChatMessage{Text: message}, gc.ThreadKey)
+}
+
+// SendCard to google chat
+func (gc *GoogleChatClient) SendCard(cards ...GoogleChatCard) error {
+	return gc.SendGoogleChatNotificationCtx(context.Background(), &GoogleChatMessage{CardsV2: cards}, gc.ThreadKey)
+}
+
+// SendGoogleChatNotification with json structure, messages with the same
+// thread key are replied in the same thread
+func (gc *GoogleChatClient) SendGoogleChatNotification(googleChatMessage *GoogleChatMessage, threadKey string) error {
+	return gc.SendGoogleChatNotificationCtx(context.Background(), googleChatMessage, threadKey)
+}
+
+// SendGoogleChatNotificationCtx is SendGoogleChatNotification canceled with the context
+func (gc *GoogleChatClient) SendGoogleChatNotificationCtx(ctx context.Context, googleChatMessage *GoogleChatMessage, threadKey string) error {
+	body, err := json.Marshal(googleChatMessage)
+	if err != nil {
+		return err
+	}
+	URL := gc.WebHookURL
+	if threadKey != "" {
+		u, err := url.Parse(URL)
+		if err != nil {
+			return err
+		}
+		query := u.Query()
+		query.Set("threadKey", threadKey)
+		query.Set("messageReplyOption", "REPLY_MESSAGE_FALLBACK_TO_NEW_THREAD")
+		u.RawQuery = query.Encode()
+		URL = u.String()
+	}
+	req, err := retryablehttp.NewRequest(http.MethodPost, URL, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/json; charset=UTF-8")
+
+	resp, err := doTimeout(gc.client, req.WithContext(ctx), gc.TimeOut)
+	if err != nil {
+		return err
+	}
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck // silent fail
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newResponseError(resp, buf)
+	}
+	return nil
+}
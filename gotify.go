@@ -0,0 +1,108 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// DefaultGotifyTimeout to conclude operations
+const DefaultGotifyTimeout = 5 * time.Second
+
+// Gotify priorities of the severity helpers, the android client shows
+// 1-3 silently, plays a sound from 4 and pops up from 8
+const (
+	GotifyPriorityInfo    = 2
+	GotifyPriorityWarning = 5
+	GotifyPriorityError   = 8
+)
+
+// GotifyClient handling gotify applications
+type GotifyClient struct {
+	client *retryablehttp.Client
+	// URL of the gotify server
+	URL string
+	// Token of the gotify application
+	Token    string
+	Title    string
+	Markdown bool
+	TimeOut  time.Duration
+}
+
+// GotifyMessage json structure
+type GotifyMessage struct {
+	Title    string                 `json:"title,omitempty"`
+	Message  string                 `json:"message"`
+	Priority int                    `json:"priority"`
+	Extras   map[string]interface{} `json:"extras,omitempty"`
+}
+
+// SendInfo to gotify
+func (gc *GotifyClient) SendInfo(message string) error {
+	return gc.SendInfoCtx(context.Background(), message)
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (gc *GotifyClient) SendInfoCtx(ctx context.Context, message string) error {
+	return gc.send(ctx, message, GotifyPriorityInfo)
+}
+
+// SendWarning to gotify
+func (gc *GotifyClient) SendWarning(message string) error {
+	return gc.send(context.Background(), message, GotifyPriorityWarning)
+}
+
+// SendError to gotify
+func (gc *GotifyClient) SendError(message string) error {
+	return gc.send(context.Background(), message, GotifyPriorityError)
+}
+
+func (gc *GotifyClient) send(ctx context.Context, message string, priority int) error {
+	gotifyMessage := &GotifyMessage{Title: gc.Title, Message: message, Priority: priority}
+	if gc.Markdown {
+		gotifyMessage.Extras = map[string]interface{}{
+			"client::display": map[string]string{"contentType": "text/markdown"},
+		}
+	}
+	return gc.SendGotifyNotificationCtx(ctx, gotifyMessage)
+}
+
+// SendGotifyNotification with json structure
+func (gc *GotifyClient) SendGotifyNotification(gotifyMessage *GotifyMessage) error {
+	return gc.SendGotifyNotificationCtx(context.Background(), gotifyMessage)
+}
+
+// SendGotifyNotificationCtx is SendGotifyNotification canceled with the context
+func (gc *GotifyClient) SendGotifyNotificationCtx(ctx context.Context, gotifyMessage *GotifyMessage) error {
+	body, err := json.Marshal(gotifyMessage)
+	if err != nil {
+		return err
+	}
+	req, err := retryablehttp.NewRequest(http.MethodPost, strings.TrimSuffix(gc.URL, "/")+"/message", body)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("X-Gotify-Key", gc.Token)
+
+	resp, err := doTimeout(gc.client, req.WithContext(ctx), gc.TimeOut)
+	if err != nil {
+		return err
+	}
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck // silent fail
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newResponseError(resp, buf)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// DefaultGrafanaTimeout to conclude operations
+const DefaultGrafanaTimeout = 5 * time.Second
+
+// GrafanaClient writes notifications as grafana annotations
+type GrafanaClient struct {
+	client *retryablehttp.Client
+	URL    string
+	APIKey string
+	// DashboardUID and PanelID scope the annotation, organization wide if empty
+	DashboardUID string
+	PanelID      int
+	Tags         []string
+	TimeOut      time.Duration
+}
+
+// GrafanaAnnotation json structure
+type GrafanaAnnotation struct {
+	DashboardUID string   `json:"dashboardUID,omitempty"`
+	PanelID      int      `json:"panelId,omitempty"`
+	Time         int64    `json:"time"`
+	Tags         []string `json:"tags,omitempty"`
+	Text         string   `json:"text"`
+}
+
+// SendInfo to grafana
+func (gc *GrafanaClient) SendInfo(message string) error {
+	return gc.SendInfoCtx(context.Background(), message)
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (gc *GrafanaClient) SendInfoCtx(ctx context.Context, message string) error {
+	return gc.SendAnnotationCtx(ctx, &GrafanaAnnotation{
+		DashboardUID: gc.DashboardUID,
+		PanelID:      gc.PanelID,
+		Time:         time.Now().UnixNano() / int64(time.Millisecond),
+		Tags:         gc.Tags,
+		Text:         message,
+	})
+}
+
+// SendAnnotation with json structure
+func (gc *GrafanaClient) SendAnnotation(annotation *GrafanaAnnotation) error {
+	return gc.SendAnnotationCtx(context.Background(), annotation)
+}
+
+// SendAnnotationCtx is SendAnnotation canceled with the context
+func (gc *GrafanaClient) SendAnnotationCtx(ctx context.Context, annotation *GrafanaAnnotation) error {
+	body, err := json.Marshal(annotation)
+	if err != nil {
+		return err
+	}
+	req, err := retryablehttp.NewRequest(http.MethodPost, strings.TrimSuffix(gc.URL, "/")+"/api/annotations", body)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Authorization", "Bearer "+gc.APIKey)
+
+	resp, err := doTimeout(gc.client, req.WithContext(ctx), gc.TimeOut)
+	if err != nil {
+		return err
+	}
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck // silent fail
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newResponseError(resp, buf)
+	}
+	return nil
+}
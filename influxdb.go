@@ -0,0 +1,104 @@
+package notify
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// DefaultInfluxDBTimeout to conclude operations
+const DefaultInfluxDBTimeout = 5 * time.Second
+
+// InfluxDBClient writes notifications as events points to InfluxDB v2
+type InfluxDBClient struct {
+	client *retryablehttp.Client
+	URL    string
+	Token  string
+	Org    string
+	Bucket string
+	// Measurement of the points, defaults to events
+	Measurement string
+	Tags        map[string]string
+	TimeOut     time.Duration
+}
+
+var (
+	influxKeyEscaper    = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+	influxStringEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+)
+
+// SendInfo to influxdb
+func (ic *InfluxDBClient) SendInfo(message string) error {
+	return ic.SendInfoCtx(context.Background(), message)
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (ic *InfluxDBClient) SendInfoCtx(ctx context.Context, message string) error {
+	return ic.WriteCtx(ctx, ic.point(message, time.Now()))
+}
+
+// point renders the message in line protocol
+func (ic *InfluxDBClient) point(message string, at time.Time) string {
+	measurement := ic.Measurement
+	if measurement == "" {
+		measurement = "events"
+	}
+	var line strings.Builder
+	line.WriteString(influxKeyEscaper.Replace(measurement))
+
+	keys := make([]string, 0, len(ic.Tags))
+	for key := range ic.Tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		line.WriteString("," + influxKeyEscaper.Replace(key) + "=" + influxKeyEscaper.Replace(ic.Tags[key]))
+	}
+	line.WriteString(` text="` + influxStringEscaper.Replace(message) + `" `)
+	line.WriteString(strconv.FormatInt(at.UnixNano(), 10))
+	return line.String()
+}
+
+// Write line protocol points to the bucket
+func (ic *InfluxDBClient) Write(lines ...string) error {
+	return ic.WriteCtx(context.Background(), lines...)
+}
+
+// WriteCtx is Write canceled with the context
+func (ic *InfluxDBClient) WriteCtx(ctx context.Context, lines ...string) error {
+	params := url.Values{}
+	params.Set("org", ic.Org)
+	params.Set("bucket", ic.Bucket)
+	params.Set("precision", "ns")
+	URL := strings.TrimSuffix(ic.URL, "/") + "/api/v2/write?" + params.Encode()
+
+	req, err := retryablehttp.NewRequest(http.MethodPost, URL, []byte(strings.Join(lines, "\n")))
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "text/plain; charset=utf-8")
+	req.Header.Add("Authorization", "Token "+ic.Token)
+
+	resp, err := doTimeout(ic.client, req.WithContext(ctx), ic.TimeOut)
+	if err != nil {
+		return err
+	}
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck // silent fail
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return newResponseError(resp, buf)
+	}
+	return nil
+}
@@ -31,10 +31,12 @@ type ConfigFile struct {
 	TelegramChatID string `yaml:"telegram_chat_id,omitempty"`
 	Telegram       bool   `yaml:"telegram,omitempty"`
 
-	Interval    int    `yaml:"interval,omitempty"`
-	HTTPMessage string `yaml:"http_message,omitempty"`
-	DNSMessage  string `yaml:"dns_message,omitempty"`
-	CLIMessage  string `yaml:"cli_message,omitempty"`
+	Interval      int    `yaml:"interval,omitempty"`
+	HTTPMessage   string `yaml:"http_message,omitempty"`
+	DNSMessage    string `yaml:"dns_message,omitempty"`
+	CLIMessage    string `yaml:"cli_message,omitempty"`
+	BatchInterval int    `yaml:"batch_interval,omitempty"`
+	BatchSize     int    `yaml:"batch_size,omitempty"`
 }
 
 // GetConfigDirectory from the system
@@ -8,6 +8,7 @@ import (
 	"os"
 
 	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/notify/config"
 )
 
 // Options of the internal runner
@@ -29,10 +30,13 @@ type Options struct {
 	NoColor                 bool
 	Silent                  bool
 	Version                 bool
+	ValidateConfig          bool
 	Interval                int
 	HTTPMessage             string
 	DNSMessage              string
 	CLIMessage              string
+	BatchInterval           int
+	BatchSize               int
 }
 
 // ParseConfigFileOrOptions combining all settings
@@ -53,12 +57,15 @@ func ParseConfigFileOrOptions() *Options {
 	flag.BoolVar(&options.Telegram, "telegram", false, "Enable Telegram")
 	flag.BoolVar(&options.Silent, "silent", false, "Don't print the banner")
 	flag.BoolVar(&options.Version, "version", false, "Show version of notify")
+	flag.BoolVar(&options.ValidateConfig, "validate-config", false, "Validate the configuration file and exit")
 	flag.BoolVar(&options.Verbose, "v", false, "Show Verbose output")
 	flag.BoolVar(&options.NoColor, "no-color", false, "Don't Use colors in output")
 	flag.IntVar(&options.Interval, "interval", 2, "Polling interval in seconds")
 	flag.StringVar(&options.HTTPMessage, "message-http", defaultHTTPMessage, "HTTP Message")
 	flag.StringVar(&options.DNSMessage, "message-dns", defaultDNSMessage, "DNS Message")
 	flag.StringVar(&options.CLIMessage, "message-cli", defaultCLIMessage, "CLI Message")
+	flag.IntVar(&options.BatchInterval, "batch-interval", 0, "Combine stdin messages sent within the interval in seconds")
+	flag.IntVar(&options.BatchSize, "batch-size", 0, "Maximum number of stdin messages combined in one notification")
 
 	flag.Parse()
 
@@ -78,6 +85,9 @@ func ParseConfigFileOrOptions() *Options {
 	if err != nil {
 		gologger.Errorf("Program exiting: %s\n", err)
 	}
+	if options.ValidateConfig {
+		options.validateConfig(defaultConfigPath)
+	}
 	options.MergeFromConfig(defaultConfigPath, true)
 
 	// Show the user the banner
@@ -237,4 +247,39 @@ func (options *Options) MergeFromConfig(configFileName string, ignoreError bool)
 	if configFile.Interval > 0 {
 		options.Interval = configFile.Interval
 	}
+	if configFile.BatchInterval > 0 {
+		options.BatchInterval = configFile.BatchInterval
+	}
+	if configFile.BatchSize > 0 {
+		options.BatchSize = configFile.BatchSize
+	}
+}
+
+// validateConfig reports the problems of the configuration file, and of the
+// provider configuration when there is one, and exits
+func (options *Options) validateConfig(configFileName string) {
+	problems, err := validateConfigFile(configFileName)
+	if err != nil {
+		gologger.Fatalf("Could not read configuration file %s: %s\n", configFileName, err)
+	}
+	for _, problem := range problems {
+		gologger.Errorf("%s: %s\n", configFileName, problem)
+	}
+	invalid := len(problems) > 0
+
+	if providerConfigFileName, err := config.DefaultPath(); err == nil && fileExists(providerConfigFileName) {
+		providerProblems, err := config.ValidateConfig(providerConfigFileName)
+		if err != nil {
+			gologger.Fatalf("Could not read provider configuration file %s: %s\n", providerConfigFileName, err)
+		}
+		for _, problem := range providerProblems {
+			gologger.Errorf("%s: %s\n", providerConfigFileName, problem)
+		}
+		invalid = invalid || len(providerProblems) > 0
+	}
+	if invalid {
+		os.Exit(1)
+	}
+	gologger.Infof("Configuration file %s is valid\n", configFileName)
+	os.Exit(0)
 }
@@ -42,6 +42,8 @@ func NewRunner(options *Options) (*Runner, error) {
 		TelegramAPIKey:          options.TelegramAPIKey,
 		TelegramChatID:          options.TelegramChatID,
 		Telegram:                options.Telegram,
+		CoalesceWindow:          time.Duration(options.BatchInterval) * time.Second,
+		CoalesceMaxMessages:     options.BatchSize,
 	})
 	if err != nil {
 		return nil, err
@@ -62,9 +64,15 @@ func (r *Runner) Run() error {
 			)
 			msg = rr.Replace(r.options.CLIMessage)
 			gologger.Printf(msg)
+			if r.options.BatchInterval > 0 {
+				//nolint:errcheck // silent fail
+				r.notifier.Enqueue(msg)
+				continue
+			}
 			//nolint:errcheck // silent fail
 			r.notifier.SendNotification(msg)
 		}
+		r.notifier.Close()
 		os.Exit(0)
 	}
 
@@ -0,0 +1,140 @@
+package runner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/Shopify/yaml"
+	"github.com/projectdiscovery/notify/config"
+)
+
+// ConfigSchema is the json schema of the configuration file
+const ConfigSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "$id": "https://github.com/projectdiscovery/notify/notify.conf.schema.json",
+  "title": "notify configuration",
+  "type": "object",
+  "additionalProperties": false,
+  "properties": {
+    "burp_biid": {"type": "string", "description": "burp collaborator unique id"},
+    "slack_webhook_url": {"type": "string", "description": "slack webhook url"},
+    "slack_username": {"type": "string", "description": "slack username"},
+    "slack_channel": {"type": "string", "description": "slack channel"},
+    "slack": {"type": "boolean", "description": "enable slack"},
+    "discord_webhook_url": {"type": "string", "description": "discord webhook url"},
+    "discord_username": {"type": "string", "description": "discord username"},
+    "discord_avatar": {"type": "string", "description": "discord avatar url"},
+    "discord": {"type": "boolean", "description": "enable discord"},
+    "telegram_apikey": {"type": "string", "description": "telegram bot api key"},
+    "telegram_chat_id": {"type": "string", "description": "telegram chat id"},
+    "telegram": {"type": "boolean", "description": "enable telegram"},
+    "interval": {"type": "integer", "minimum": 1, "description": "polling interval in seconds"},
+    "http_message": {"type": "string", "description": "http interaction message template"},
+    "dns_message": {"type": "string", "description": "dns interaction message template"},
+    "cli_message": {"type": "string", "description": "stdin message template"},
+    "batch_interval": {"type": "integer", "minimum": 1, "description": "interval in seconds combining stdin messages"},
+    "batch_size": {"type": "integer", "minimum": 1, "description": "maximum stdin messages combined in a notification"}
+  },
+  "allOf": [
+    {"if": {"properties": {"slack": {"const": true}}, "required": ["slack"]}, "then": {"required": ["slack_webhook_url"]}},
+    {"if": {"properties": {"discord": {"const": true}}, "required": ["discord"]}, "then": {"required": ["discord_webhook_url"]}},
+    {"if": {"properties": {"telegram": {"const": true}}, "required": ["telegram"]}, "then": {"required": ["telegram_apikey", "telegram_chat_id"]}}
+  ]
+}`
+
+// configSchema is the subset of json schema used by ConfigSchema
+type configSchema struct {
+	Properties map[string]struct {
+		Type    string   `json:"type"`
+		Minimum *float64 `json:"minimum"`
+	} `json:"properties"`
+	AllOf []struct {
+		If struct {
+			Properties map[string]struct {
+				Const interface{} `json:"const"`
+			} `json:"properties"`
+		} `json:"if"`
+		Then struct {
+			Required []string `json:"required"`
+		} `json:"then"`
+	} `json:"allOf"`
+}
+
+// validateConfigFile checks the runner configuration file against ConfigSchema
+// reporting unknown keys, type errors and missing credentials, the provider
+// configuration is checked by config.ValidateConfig
+func validateConfigFile(file string) ([]*config.ConfigError, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	settings := make(map[string]interface{})
+	err = yaml.NewDecoder(f).Decode(&settings)
+	//nolint:errcheck // silent fail
+	f.Close()
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	return validateConfig(settings)
+}
+
+func validateConfig(settings map[string]interface{}) ([]*config.ConfigError, error) {
+	var schema configSchema
+	if err := json.Unmarshal([]byte(ConfigSchema), &schema); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var problems []*config.ConfigError
+	for _, key := range keys {
+		property, ok := schema.Properties[key]
+		if !ok {
+			problems = append(problems, &config.ConfigError{Key: key, Err: errors.New("unknown key")})
+			continue
+		}
+		value := settings[key]
+		var valid bool
+		switch property.Type {
+		case "string":
+			_, valid = value.(string)
+		case "boolean":
+			_, valid = value.(bool)
+		case "integer":
+			var number int
+			number, valid = value.(int)
+			if valid && property.Minimum != nil && float64(number) < *property.Minimum {
+				problems = append(problems, &config.ConfigError{Key: key, Err: fmt.Errorf("must be at least %v", *property.Minimum)})
+			}
+		}
+		if !valid {
+			problems = append(problems, &config.ConfigError{Key: key, Err: fmt.Errorf("expected %s, got %T", property.Type, value)})
+		}
+	}
+
+	for _, rule := range schema.AllOf {
+		matches := true
+		for key, condition := range rule.If.Properties {
+			if value, ok := settings[key]; !ok || value != condition.Const {
+				matches = false
+			}
+		}
+		if !matches {
+			continue
+		}
+		for _, key := range rule.Then.Required {
+			if value, ok := settings[key]; !ok || value == "" {
+				problems = append(problems, &config.ConfigError{Key: key, Err: errors.New("required by enabled provider")})
+			}
+		}
+	}
+	return problems, nil
+}
@@ -0,0 +1,264 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// DefaultIRCTimeout to conclude operations
+const DefaultIRCTimeout = 30 * time.Second
+
+// ircMaxLineLength keeps PRIVMSG lines below the 512 bytes protocol limit with the prefix
+const ircMaxLineLength = 400
+
+// ErrIRCAuth is returned when sasl authentication fails
+var ErrIRCAuth = errors.New("irc sasl authentication failed")
+
+// IRCClient connects to a network, joins the channel, sends the message and disconnects
+type IRCClient struct {
+	// Server is the host:port of the network
+	Server string
+	TLS    bool
+	Nick   string
+	// Password is the server password, SASLUser and SASLPassword authenticate with sasl plain
+	Password     string
+	SASLUser     string
+	SASLPassword string
+	Channel      string
+	ChannelKey   string
+	TimeOut      time.Duration
+
+	capture captureFunc
+}
+
+// ircConn is a registered irc connection
+type ircConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// SendInfo to irc
+func (ic *IRCClient) SendInfo(message string) error {
+	return ic.SendInfoCtx(context.Background(), message)
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (ic *IRCClient) SendInfoCtx(ctx context.Context, message string) error {
+	return ic.SendIRCNotificationCtx(ctx, message)
+}
+
+// SendIRCNotification sends every line of the message to the channel
+func (ic *IRCClient) SendIRCNotification(message string) error {
+	return ic.SendIRCNotificationCtx(context.Background(), message)
+}
+
+// SendIRCNotificationCtx is SendIRCNotification canceled with the context
+func (ic *IRCClient) SendIRCNotificationCtx(ctx context.Context, message string) error {
+	var privmsgs []string
+	for _, line := range strings.Split(message, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line == "" {
+			continue
+		}
+		for _, chunk := range splitMessage(line, ircMaxLineLength) {
+			privmsgs = append(privmsgs, "PRIVMSG "+ic.Channel+" :"+chunk)
+		}
+	}
+	if ic.capture != nil {
+		ic.capture("irc://"+ic.Server, []byte(strings.Join(privmsgs, "\r\n")))
+		return nil
+	}
+
+	c, err := ic.connect(ctx)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck // silent fail
+	defer c.conn.Close()
+
+	join := "JOIN " + ic.Channel
+	if ic.ChannelKey != "" {
+		join += " " + ic.ChannelKey
+	}
+	if err := c.write(join); err != nil {
+		return err
+	}
+	// 366 ends the names list sent once joined
+	if _, err := c.waitFor("366", "403", "471", "473", "474", "475"); err != nil {
+		return err
+	}
+
+	for _, privmsg := range privmsgs {
+		if err := c.write(privmsg); err != nil {
+			return err
+		}
+	}
+	if err := c.write("QUIT :bye"); err != nil {
+		return err
+	}
+	// wait for the server to close the link so the messages are flushed
+	//nolint:errcheck // closing link
+	c.waitFor("ERROR")
+	return nil
+}
+
+// connect registers on the network authenticating with sasl when configured
+func (ic *IRCClient) connect(ctx context.Context) (*ircConn, error) {
+	timeout := ic.TimeOut
+	if timeout == 0 {
+		timeout = DefaultIRCTimeout
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	deadline := socketDeadline(ctx, timeout)
+	dialer := &net.Dialer{Timeout: timeout, Deadline: deadline}
+	var conn net.Conn
+	var err error
+	if ic.TLS {
+		host, _, splitErr := net.SplitHostPort(ic.Server)
+		if splitErr != nil {
+			return nil, splitErr
+		}
+		conn, err = tls.DialWithDialer(dialer, "tcp", ic.Server, &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12})
+	} else {
+		conn, err = dialer.Dial("tcp", ic.Server)
+	}
+	if err != nil {
+		return nil, err
+	}
+	//nolint:errcheck // bounds the whole exchange
+	conn.SetDeadline(deadline)
+	c := &ircConn{conn: conn, reader: bufio.NewReader(conn)}
+
+	if err := ic.register(c); err != nil {
+		//nolint:errcheck // silent fail
+		conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+func (ic *IRCClient) register(c *ircConn) error {
+	nick := ic.Nick
+	if nick == "" {
+		nick = "notify"
+	}
+	sasl := ic.SASLUser != ""
+	if sasl {
+		if err := c.write("CAP REQ :sasl"); err != nil {
+			return err
+		}
+	}
+	if ic.Password != "" {
+		if err := c.write("PASS " + ic.Password); err != nil {
+			return err
+		}
+	}
+	if err := c.write("NICK " + nick); err != nil {
+		return err
+	}
+	if err := c.write("USER " + nick + " 0 * :" + nick); err != nil {
+		return err
+	}
+
+	if sasl {
+		if fields, err := c.waitFor("CAP"); err != nil {
+			return err
+		} else if len(fields) < 4 || fields[3] != "ACK" {
+			return ErrIRCAuth
+		}
+		if err := c.write("AUTHENTICATE PLAIN"); err != nil {
+			return err
+		}
+		if _, err := c.waitFor("AUTHENTICATE"); err != nil {
+			return err
+		}
+		credentials := base64.StdEncoding.EncodeToString([]byte(ic.SASLUser + "\x00" + ic.SASLUser + "\x00" + ic.SASLPassword))
+		if err := c.write("AUTHENTICATE " + credentials); err != nil {
+			return err
+		}
+		if fields, err := c.waitFor("903", "902", "904", "905", "906"); err != nil {
+			return err
+		} else if fields[1] != "903" {
+			return ErrIRCAuth
+		}
+		if err := c.write("CAP END"); err != nil {
+			return err
+		}
+	}
+
+	for {
+		fields, err := c.waitFor("001", "433")
+		if err != nil {
+			return err
+		}
+		if fields[1] == "001" {
+			return nil
+		}
+		// nickname in use
+		nick += "_"
+		if err := c.write("NICK " + nick); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *ircConn) write(line string) error {
+	_, err := c.conn.Write([]byte(line + "\r\n"))
+	return err
+}
+
+// waitFor reads lines, answering pings, until one of the commands or numerics
+// is received. Error numerics among them are returned as errors.
+func (c *ircConn) waitFor(commands ...string) ([]string, error) {
+	for {
+		line, err := c.reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "PING" {
+			if err := c.write("PONG " + strings.TrimPrefix(line, "PING ")); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if fields[0] == "AUTHENTICATE" || fields[0] == "ERROR" {
+			// unprefixed commands
+			fields = append([]string{""}, fields...)
+		}
+		if len(fields) < 2 {
+			continue
+		}
+		for _, command := range commands {
+			if fields[1] != command {
+				continue
+			}
+			if isIRCJoinError(command) {
+				return nil, fmt.Errorf("irc: %s", line)
+			}
+			return fields, nil
+		}
+	}
+}
+
+// isIRCJoinError reports the numerics of failed joins
+func isIRCJoinError(numeric string) bool {
+	switch numeric {
+	case "403", "471", "473", "474", "475":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,184 @@
+package notify
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// DefaultJiraTimeout to conclude operations
+const DefaultJiraTimeout = 10 * time.Second
+
+// DefaultJiraIssueType is used when none is configured
+const DefaultJiraIssueType = "Task"
+
+// jiraMaxSummary is the longest summary accepted by jira
+const jiraMaxSummary = 255
+
+// JiraClient opens an issue per notification
+type JiraClient struct {
+	client *retryablehttp.Client
+	// URL of the jira instance as https://example.atlassian.net
+	URL string
+	// Username and Token authenticate jira cloud, Token alone is a server personal access token
+	Username   string
+	Token      string
+	ProjectKey string
+	IssueType  string
+	Labels     []string
+	// Correlate comments the open issue of an identical summary instead of opening a new one
+	Correlate bool
+	TimeOut   time.Duration
+}
+
+// JiraIssue is a created or matched issue
+type JiraIssue struct {
+	ID  string `json:"id"`
+	Key string `json:"key"`
+}
+
+// correlationLabel identifies the issues opened for the same summary
+func correlationLabel(summary string) string {
+	sum := sha256.Sum256([]byte(summary))
+	return "notify-" + hex.EncodeToString(sum[:6])
+}
+
+// issueSummary returns the first line of the message bounded to limit characters
+func issueSummary(message string, limit int) string {
+	summary := strings.TrimSpace(message)
+	if i := strings.IndexByte(summary, '\n'); i >= 0 {
+		summary = strings.TrimSpace(summary[:i])
+	}
+	if runes := []rune(summary); len(runes) > limit {
+		summary = string(runes[:limit])
+	}
+	return summary
+}
+
+// SendInfo to jira
+func (jc *JiraClient) SendInfo(message string) error {
+	return jc.SendInfoCtx(context.Background(), message)
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (jc *JiraClient) SendInfoCtx(ctx context.Context, message string) error {
+	_, err := jc.SendIssueCtx(ctx, message, jc.Labels...)
+	return err
+}
+
+// SendIssue opens an issue with the message, or comments the correlated
+// open issue, returning the issue
+func (jc *JiraClient) SendIssue(message string, labels ...string) (*JiraIssue, error) {
+	return jc.SendIssueCtx(context.Background(), message, labels...)
+}
+
+// SendIssueCtx is SendIssue canceled with the context
+func (jc *JiraClient) SendIssueCtx(ctx context.Context, message string, labels ...string) (*JiraIssue, error) {
+	summary := issueSummary(message, jiraMaxSummary)
+	if jc.Correlate {
+		label := correlationLabel(summary)
+		labels = append(append([]string{}, labels...), label)
+		issue, err := jc.findOpenIssue(ctx, label)
+		if err != nil {
+			return nil, err
+		}
+		if issue != nil {
+			return issue, jc.AddCommentCtx(ctx, issue.Key, message)
+		}
+	}
+
+	issueType := jc.IssueType
+	if issueType == "" {
+		issueType = DefaultJiraIssueType
+	}
+	fields := map[string]interface{}{
+		"project":     map[string]string{"key": jc.ProjectKey},
+		"issuetype":   map[string]string{"name": issueType},
+		"summary":     summary,
+		"description": message,
+	}
+	if len(labels) > 0 {
+		fields["labels"] = labels
+	}
+	var issue JiraIssue
+	if err := jc.do(ctx, http.MethodPost, "/rest/api/2/issue", map[string]interface{}{"fields": fields}, &issue); err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// AddComment to the issue
+func (jc *JiraClient) AddComment(issueKey, comment string) error {
+	return jc.AddCommentCtx(context.Background(), issueKey, comment)
+}
+
+// AddCommentCtx is AddComment canceled with the context
+func (jc *JiraClient) AddCommentCtx(ctx context.Context, issueKey, comment string) error {
+	return jc.do(ctx, http.MethodPost, "/rest/api/2/issue/"+url.PathEscape(issueKey)+"/comment", map[string]string{"body": comment}, nil)
+}
+
+func (jc *JiraClient) findOpenIssue(ctx context.Context, label string) (*JiraIssue, error) {
+	jql := `project = "` + jc.ProjectKey + `" AND labels = "` + label + `" AND statusCategory != Done ORDER BY created DESC`
+	var search struct {
+		Issues []JiraIssue `json:"issues"`
+	}
+	query := url.Values{"jql": {jql}, "maxResults": {"1"}, "fields": {"key"}}
+	if err := jc.do(ctx, http.MethodGet, "/rest/api/2/search?"+query.Encode(), nil, &search); err != nil {
+		return nil, err
+	}
+	if len(search.Issues) == 0 {
+		return nil, nil
+	}
+	return &search.Issues[0], nil
+}
+
+func (jc *JiraClient) do(ctx context.Context, method, path string, payload, result interface{}) error {
+	var body interface{}
+	if payload != nil {
+		buf, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = buf
+	}
+	req, err := retryablehttp.NewRequest(method, strings.TrimSuffix(jc.URL, "/")+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Accept", "application/json")
+	if payload != nil {
+		req.Header.Add("Content-Type", "application/json")
+	}
+	if jc.Username != "" {
+		req.SetBasicAuth(jc.Username, jc.Token)
+	} else {
+		req.Header.Add("Authorization", "Bearer "+jc.Token)
+	}
+
+	resp, err := doTimeout(jc.client, req.WithContext(ctx), jc.TimeOut)
+	if err != nil {
+		return err
+	}
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck // silent fail
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return newResponseError(resp, buf)
+	}
+	if result != nil {
+		return json.Unmarshal(buf, result)
+	}
+	return nil
+}
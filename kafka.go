@@ -0,0 +1,538 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultKafkaTimeout to conclude operations
+const DefaultKafkaTimeout = 10 * time.Second
+
+// Sources of the record key, records without key are spread over the partitions
+const (
+	KafkaKeyNone     = ""
+	KafkaKeySeverity = "severity"
+	KafkaKeyTag      = "tag"
+)
+
+// kafka api keys and limits of the subset of the protocol used by the producer
+const (
+	kafkaProduce          int16 = 0
+	kafkaMetadata         int16 = 3
+	kafkaSaslHandshake    int16 = 17
+	kafkaSaslAuthenticate int16 = 36
+	// kafkaAcksAll waits for the in-sync replicas to persist the record
+	kafkaAcksAll         int16 = -1
+	kafkaMaxResponseSize       = 16 << 20
+)
+
+var (
+	// ErrKafkaAuth is returned when sasl authentication fails
+	ErrKafkaAuth = errors.New("kafka sasl authentication failed")
+	// ErrKafkaNoBrokers is returned when no bootstrap broker is configured
+	ErrKafkaNoBrokers = errors.New("kafka: no brokers configured")
+
+	errKafkaMalformed = errors.New("kafka: malformed response")
+	castagnoliTable   = crc32.MakeTable(crc32.Castagnoli)
+)
+
+// kafkaPartitionCounter spreads records without key over the partitions
+var kafkaPartitionCounter uint32
+
+// KafkaClient publishes notifications to a topic speaking the kafka protocol
+type KafkaClient struct {
+	// Brokers are the host:port bootstrap servers
+	Brokers []string
+	Topic   string
+	// KeySource selects the record key, the severity, the Tag or none
+	KeySource string
+	Tag       string
+	TLS       bool
+	// SASLUsername and SASLPassword authenticate with sasl plain
+	SASLUsername string
+	SASLPassword string
+	TimeOut      time.Duration
+
+	capture captureFunc
+}
+
+// kafkaConn is an authenticated broker connection
+type kafkaConn struct {
+	conn          net.Conn
+	addr          string
+	correlationID int32
+	// timeout the broker waits for the replicas to acknowledge a produce
+	timeout time.Duration
+}
+
+// kafkaPartition of the topic metadata
+type kafkaPartition struct {
+	id     int32
+	leader int32
+}
+
+// SendInfo to kafka
+func (kc *KafkaClient) SendInfo(message string) error {
+	return kc.SendInfoCtx(context.Background(), message)
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (kc *KafkaClient) SendInfoCtx(ctx context.Context, message string) error {
+	return kc.PublishCtx(ctx, "info", message)
+}
+
+// SendWarning to kafka
+func (kc *KafkaClient) SendWarning(message string) error {
+	return kc.PublishCtx(context.Background(), "warning", message)
+}
+
+// SendError to kafka
+func (kc *KafkaClient) SendError(message string) error {
+	return kc.PublishCtx(context.Background(), "error", message)
+}
+
+// Publish produces the message to the topic, the severity is set as record header
+func (kc *KafkaClient) Publish(severity, message string) error {
+	return kc.PublishCtx(context.Background(), severity, message)
+}
+
+// PublishCtx is Publish canceled with the context
+func (kc *KafkaClient) PublishCtx(ctx context.Context, severity, message string) error {
+	if kc.capture != nil {
+		kc.capture("kafka://"+strings.Join(kc.Brokers, ",")+"/"+kc.Topic, []byte(message))
+		return nil
+	}
+
+	c, err := kc.bootstrap(ctx)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck // silent fail
+	defer c.conn.Close()
+
+	brokers, partitions, err := c.metadata(kc.Topic)
+	if err != nil {
+		return err
+	}
+	if len(partitions) == 0 {
+		return fmt.Errorf("kafka: topic %s has no partitions", kc.Topic)
+	}
+
+	key := kc.key(severity)
+	partition := kafkaSelectPartition(key, partitions)
+	addr, ok := brokers[partition.leader]
+	if !ok {
+		return fmt.Errorf("kafka: partition %d of %s has no leader", partition.id, kc.Topic)
+	}
+
+	leader := c
+	if addr != c.addr {
+		if leader, err = kc.dial(ctx, addr); err != nil {
+			return err
+		}
+		//nolint:errcheck // silent fail
+		defer leader.conn.Close()
+	}
+	batch := kafkaRecordBatch(key, []byte(message), map[string]string{"severity": severity}, time.Now())
+	return leader.produce(kc.Topic, partition.id, batch)
+}
+
+// kafkaSelectPartition returns the partition of the record. Keyed records go to
+// the partition id murmur2(key) % partitions like with the java client, so they
+// stay ordered whatever the order of the partitions in the metadata.
+func kafkaSelectPartition(key []byte, partitions []kafkaPartition) kafkaPartition {
+	byID := append([]kafkaPartition(nil), partitions...)
+	sort.Slice(byID, func(i, j int) bool { return byID[i].id < byID[j].id })
+	if key != nil {
+		return byID[int(kafkaMurmur2(key)&0x7fffffff)%len(byID)]
+	}
+	return byID[int(atomic.AddUint32(&kafkaPartitionCounter, 1)%uint32(len(byID)))]
+}
+
+func (kc *KafkaClient) key(severity string) []byte {
+	switch kc.KeySource {
+	case KafkaKeySeverity:
+		return []byte(severity)
+	case KafkaKeyTag:
+		return []byte(kc.Tag)
+	}
+	return nil
+}
+
+// bootstrap connects to the first reachable broker
+func (kc *KafkaClient) bootstrap(ctx context.Context) (*kafkaConn, error) {
+	err := ErrKafkaNoBrokers
+	for _, broker := range kc.Brokers {
+		var c *kafkaConn
+		if c, err = kc.dial(ctx, broker); err == nil {
+			return c, nil
+		}
+	}
+	return nil, err
+}
+
+func (kc *KafkaClient) dial(ctx context.Context, addr string) (*kafkaConn, error) {
+	timeout := kc.TimeOut
+	if timeout == 0 {
+		timeout = DefaultKafkaTimeout
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	deadline := socketDeadline(ctx, timeout)
+	dialer := &net.Dialer{Timeout: timeout, Deadline: deadline}
+	var conn net.Conn
+	var err error
+	if kc.TLS {
+		host, _, splitErr := net.SplitHostPort(addr)
+		if splitErr != nil {
+			return nil, splitErr
+		}
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12})
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	//nolint:errcheck // bounds the whole exchange
+	conn.SetDeadline(deadline)
+	c := &kafkaConn{conn: conn, addr: addr, timeout: timeout}
+
+	if kc.SASLUsername != "" {
+		if err := c.authenticate(kc.SASLUsername, kc.SASLPassword); err != nil {
+			//nolint:errcheck // silent fail
+			conn.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// authenticate with sasl plain through the handshake and authenticate requests
+func (c *kafkaConn) authenticate(username, password string) error {
+	var handshake kafkaEncoder
+	handshake.string("PLAIN")
+	d, err := c.request(kafkaSaslHandshake, 1, handshake.Bytes())
+	if err != nil {
+		return err
+	}
+	if code := d.int16(); d.err != nil {
+		return d.err
+	} else if code != 0 {
+		return fmt.Errorf("%w: handshake error code %d", ErrKafkaAuth, code)
+	}
+
+	var authenticate kafkaEncoder
+	authenticate.bytes([]byte("\x00" + username + "\x00" + password))
+	if d, err = c.request(kafkaSaslAuthenticate, 0, authenticate.Bytes()); err != nil {
+		return err
+	}
+	code, message := d.int16(), d.string()
+	if d.err != nil {
+		return d.err
+	}
+	if code != 0 {
+		return fmt.Errorf("%w: %s (%d)", ErrKafkaAuth, message, code)
+	}
+	return nil
+}
+
+// metadata returns the broker addresses by node id and the partitions of the topic
+func (c *kafkaConn) metadata(topic string) (map[int32]string, []kafkaPartition, error) {
+	var e kafkaEncoder
+	e.int32(1)
+	e.string(topic)
+	d, err := c.request(kafkaMetadata, 1, e.Bytes())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	brokers := make(map[int32]string)
+	for i := d.int32(); i > 0 && d.err == nil; i-- {
+		id, host, port := d.int32(), d.string(), d.int32()
+		d.string() // rack
+		brokers[id] = net.JoinHostPort(host, strconv.Itoa(int(port)))
+	}
+	d.int32() // controller id
+
+	var partitions []kafkaPartition
+	for i := d.int32(); i > 0 && d.err == nil; i-- {
+		code, name := d.int16(), d.string()
+		d.int8() // internal
+		for j := d.int32(); j > 0 && d.err == nil; j-- {
+			d.int16() // partition error, a missing leader is reported below
+			partition := kafkaPartition{id: d.int32(), leader: d.int32()}
+			d.int32Array() // replicas
+			d.int32Array() // in-sync replicas
+			partitions = append(partitions, partition)
+		}
+		if name == topic && code != 0 {
+			return nil, nil, fmt.Errorf("kafka: metadata of %s: error code %d", topic, code)
+		}
+	}
+	if d.err != nil {
+		return nil, nil, d.err
+	}
+	return brokers, partitions, nil
+}
+
+func (c *kafkaConn) produce(topic string, partition int32, batch []byte) error {
+	var e kafkaEncoder
+	e.int16(-1) // null transactional id
+	e.int16(kafkaAcksAll)
+	e.int32(int32(c.timeout / time.Millisecond))
+	e.int32(1)
+	e.string(topic)
+	e.int32(1)
+	e.int32(partition)
+	e.bytes(batch)
+	d, err := c.request(kafkaProduce, 3, e.Bytes())
+	if err != nil {
+		return err
+	}
+
+	for i := d.int32(); i > 0 && d.err == nil; i-- {
+		d.string()
+		for j := d.int32(); j > 0 && d.err == nil; j-- {
+			d.int32()
+			code := d.int16()
+			d.int64() // base offset
+			d.int64() // log append time
+			if d.err == nil && code != 0 {
+				return fmt.Errorf("kafka: produce to %s/%d: error code %d", topic, partition, code)
+			}
+		}
+	}
+	return d.err
+}
+
+// request sends a framed request and reads its response
+func (c *kafkaConn) request(apiKey, version int16, body []byte) (*kafkaDecoder, error) {
+	c.correlationID++
+	var e kafkaEncoder
+	e.int32(0) // size, set once the request is encoded
+	e.int16(apiKey)
+	e.int16(version)
+	e.int32(c.correlationID)
+	e.string("notify")
+	e.Write(body)
+	frame := e.Bytes()
+	binary.BigEndian.PutUint32(frame, uint32(len(frame)-4))
+	if _, err := c.conn.Write(frame); err != nil {
+		return nil, err
+	}
+
+	var size [4]byte
+	if _, err := io.ReadFull(c.conn, size[:]); err != nil {
+		return nil, err
+	}
+	n := binary.BigEndian.Uint32(size[:])
+	if n < 4 || n > kafkaMaxResponseSize {
+		return nil, errKafkaMalformed
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(c.conn, buf); err != nil {
+		return nil, err
+	}
+	d := &kafkaDecoder{buf: buf}
+	if d.int32() != c.correlationID {
+		return nil, errKafkaMalformed
+	}
+	return d, nil
+}
+
+// kafkaRecordBatch encodes a v2 record batch holding a single record
+func kafkaRecordBatch(key, value []byte, headers map[string]string, now time.Time) []byte {
+	var record kafkaEncoder
+	record.int8(0)   // attributes
+	record.varint(0) // timestamp delta
+	record.varint(0) // offset delta
+	if key == nil {
+		record.varint(-1)
+	} else {
+		record.varbytes(key)
+	}
+	record.varbytes(value)
+	record.varint(int64(len(headers)))
+	for k, v := range headers {
+		record.varbytes([]byte(k))
+		record.varbytes([]byte(v))
+	}
+
+	// the crc covers the batch from the attributes to the end
+	var batch kafkaEncoder
+	timestamp := now.UnixNano() / int64(time.Millisecond)
+	batch.int16(0) // attributes, uncompressed
+	batch.int32(0) // last offset delta
+	batch.int64(timestamp)
+	batch.int64(timestamp)
+	batch.int64(-1) // producer id
+	batch.int16(-1) // producer epoch
+	batch.int32(-1) // base sequence
+	batch.int32(1)
+	batch.varbytes(record.Bytes())
+
+	var e kafkaEncoder
+	e.int64(0)
+	// length from the partition leader epoch: epoch, magic and crc precede the batch
+	e.int32(int32(4 + 1 + 4 + batch.Len()))
+	e.int32(-1)
+	e.int8(2)
+	e.int32(int32(crc32.Checksum(batch.Bytes(), castagnoliTable)))
+	e.Write(batch.Bytes())
+	return e.Bytes()
+}
+
+// kafkaMurmur2 is the hash of the default partitioner of the java client
+func kafkaMurmur2(data []byte) int32 {
+	const m uint32 = 0x5bd1e995
+	length := len(data)
+	h := uint32(0x9747b28c) ^ uint32(length)
+	for i := 0; i+4 <= length; i += 4 {
+		k := binary.LittleEndian.Uint32(data[i:])
+		k *= m
+		k ^= k >> 24
+		k *= m
+		h *= m
+		h ^= k
+	}
+	tail := length &^ 3
+	switch length & 3 {
+	case 3:
+		h ^= uint32(data[tail+2]) << 16
+		fallthrough
+	case 2:
+		h ^= uint32(data[tail+1]) << 8
+		fallthrough
+	case 1:
+		h ^= uint32(data[tail])
+		h *= m
+	}
+	h ^= h >> 13
+	h *= m
+	h ^= h >> 15
+	return int32(h)
+}
+
+// kafkaEncoder writes the big endian primitives of the protocol
+type kafkaEncoder struct {
+	bytes.Buffer
+}
+
+func (e *kafkaEncoder) int8(v int8) {
+	e.WriteByte(byte(v))
+}
+
+func (e *kafkaEncoder) int16(v int16) {
+	var b [2]byte
+	binary.BigEndian.PutUint16(b[:], uint16(v))
+	e.Write(b[:])
+}
+
+func (e *kafkaEncoder) int32(v int32) {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], uint32(v))
+	e.Write(b[:])
+}
+
+func (e *kafkaEncoder) int64(v int64) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(v))
+	e.Write(b[:])
+}
+
+func (e *kafkaEncoder) string(v string) {
+	e.int16(int16(len(v)))
+	e.WriteString(v)
+}
+
+func (e *kafkaEncoder) bytes(v []byte) {
+	e.int32(int32(len(v)))
+	e.Write(v)
+}
+
+// varint writes a zigzag encoded varint as used inside record batches
+func (e *kafkaEncoder) varint(v int64) {
+	var b [binary.MaxVarintLen64]byte
+	e.Write(b[:binary.PutVarint(b[:], v)])
+}
+
+func (e *kafkaEncoder) varbytes(v []byte) {
+	e.varint(int64(len(v)))
+	e.Write(v)
+}
+
+// kafkaDecoder reads responses, the first out of bounds read sets err
+type kafkaDecoder struct {
+	buf []byte
+	err error
+}
+
+func (d *kafkaDecoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(d.buf) {
+		d.err = errKafkaMalformed
+		return nil
+	}
+	b := d.buf[:n]
+	d.buf = d.buf[n:]
+	return b
+}
+
+func (d *kafkaDecoder) int8() int8 {
+	if b := d.next(1); b != nil {
+		return int8(b[0])
+	}
+	return 0
+}
+
+func (d *kafkaDecoder) int16() int16 {
+	if b := d.next(2); b != nil {
+		return int16(binary.BigEndian.Uint16(b))
+	}
+	return 0
+}
+
+func (d *kafkaDecoder) int32() int32 {
+	if b := d.next(4); b != nil {
+		return int32(binary.BigEndian.Uint32(b))
+	}
+	return 0
+}
+
+func (d *kafkaDecoder) int64() int64 {
+	if b := d.next(8); b != nil {
+		return int64(binary.BigEndian.Uint64(b))
+	}
+	return 0
+}
+
+// string reads a nullable string, null is returned as empty
+func (d *kafkaDecoder) string() string {
+	n := d.int16()
+	if n < 0 {
+		return ""
+	}
+	return string(d.next(int(n)))
+}
+
+func (d *kafkaDecoder) int32Array() {
+	if n := d.int32(); n > 0 {
+		d.next(int(n) * 4)
+	}
+}
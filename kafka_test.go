@@ -0,0 +1,52 @@
+package notify
+
+import "testing"
+
+func TestKafkaMurmur2(t *testing.T) {
+	// vectors of the java client utils tests
+	tests := []struct {
+		key  string
+		hash int32
+	}{
+		{"21", -973932308},
+		{"foobar", -790332482},
+		{"a-little-bit-long-string", -985981536},
+		{"a-little-bit-longer-string", -1486304829},
+		{"lkjh234lh9fiuh90y23oiuhsafujhadof229phr9h19h89h8", -58897971},
+		{"abc", 479470107},
+	}
+	for _, test := range tests {
+		if hash := kafkaMurmur2([]byte(test.key)); hash != test.hash {
+			t.Errorf("kafkaMurmur2(%q) = %d, want %d", test.key, hash, test.hash)
+		}
+	}
+}
+
+func TestKafkaSelectPartitionKeyed(t *testing.T) {
+	partitions := []kafkaPartition{{id: 2, leader: 1}, {id: 0, leader: 2}, {id: 1, leader: 3}}
+	reversed := []kafkaPartition{partitions[2], partitions[1], partitions[0]}
+	key := []byte("foobar")
+	want := int32(int(kafkaMurmur2(key)&0x7fffffff) % len(partitions))
+
+	for _, order := range [][]kafkaPartition{partitions, reversed} {
+		if partition := kafkaSelectPartition(key, order); partition.id != want {
+			t.Errorf("kafkaSelectPartition() = partition %d, want %d", partition.id, want)
+		}
+	}
+	if partitions[0].id != 2 {
+		t.Error("kafkaSelectPartition() reordered the metadata partitions")
+	}
+}
+
+func TestKafkaSelectPartitionRoundRobin(t *testing.T) {
+	partitions := []kafkaPartition{{id: 0}, {id: 1}, {id: 2}}
+	seen := make(map[int32]int)
+	for i := 0; i < 3*len(partitions); i++ {
+		seen[kafkaSelectPartition(nil, partitions).id]++
+	}
+	for _, partition := range partitions {
+		if seen[partition.id] != 3 {
+			t.Errorf("partition %d selected %d times, want 3", partition.id, seen[partition.id])
+		}
+	}
+}
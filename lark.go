@@ -0,0 +1,171 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// DefaultLarkTimeout to conclude operations
+const DefaultLarkTimeout = 5 * time.Second
+
+// Card header templates of the severity helpers
+const (
+	LarkTemplateInfo    = "green"
+	LarkTemplateWarning = "orange"
+	LarkTemplateError   = "red"
+)
+
+// LarkClient handling feishu and lark custom bot webhooks
+type LarkClient struct {
+	client     *retryablehttp.Client
+	WebHookURL string
+	// Secret signs the requests of bots with the signature verification setting
+	Secret string
+	// Title of the card headers
+	Title   string
+	TimeOut time.Duration
+}
+
+// LarkMessage json structure
+type LarkMessage struct {
+	Timestamp string       `json:"timestamp,omitempty"`
+	Sign      string       `json:"sign,omitempty"`
+	MsgType   string       `json:"msg_type"`
+	Content   *LarkContent `json:"content,omitempty"`
+	Card      *LarkCard    `json:"card,omitempty"`
+}
+
+// LarkContent of text messages
+type LarkContent struct {
+	Text string `json:"text"`
+}
+
+// LarkCard is an interactive card
+type LarkCard struct {
+	Config   map[string]interface{}   `json:"config,omitempty"`
+	Header   *LarkCardHeader          `json:"header,omitempty"`
+	Elements []map[string]interface{} `json:"elements"`
+}
+
+// LarkCardHeader is the colored title of a card
+type LarkCardHeader struct {
+	Title    LarkCardText `json:"title"`
+	Template string       `json:"template,omitempty"`
+}
+
+// LarkCardText is a plain_text or lark_md text
+type LarkCardText struct {
+	Tag     string `json:"tag"`
+	Content string `json:"content"`
+}
+
+// larkResponse of the bot api, errors are reported with status 200
+type larkResponse struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+}
+
+// NewLarkCard returns a card with a header of the template color and the markdown text
+func NewLarkCard(title, template, text string) *LarkCard {
+	return &LarkCard{
+		Config: map[string]interface{}{"wide_screen_mode": true},
+		Header: &LarkCardHeader{Title: LarkCardText{Tag: "plain_text", Content: title}, Template: template},
+		Elements: []map[string]interface{}{
+			{"tag": "div", "text": LarkCardText{Tag: "lark_md", Content: text}},
+		},
+	}
+}
+
+// SendInfo to lark
+func (lc *LarkClient) SendInfo(message string) error {
+	return lc.SendInfoCtx(context.Background(), message)
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (lc *LarkClient) SendInfoCtx(ctx context.Context, message string) error {
+	return lc.SendCardCtx(ctx, NewLarkCard(lc.title("Info"), LarkTemplateInfo, message))
+}
+
+// SendWarning to lark
+func (lc *LarkClient) SendWarning(message string) error {
+	return lc.SendCardCtx(context.Background(), NewLarkCard(lc.title("Warning"), LarkTemplateWarning, message))
+}
+
+// SendError to lark
+func (lc *LarkClient) SendError(message string) error {
+	return lc.SendCardCtx(context.Background(), NewLarkCard(lc.title("Error"), LarkTemplateError, message))
+}
+
+func (lc *LarkClient) title(severity string) string {
+	if lc.Title != "" {
+		return lc.Title
+	}
+	return severity
+}
+
+// SendCard posts an interactive card
+func (lc *LarkClient) SendCard(card *LarkCard) error {
+	return lc.SendCardCtx(context.Background(), card)
+}
+
+// SendCardCtx is SendCard canceled with the context
+func (lc *LarkClient) SendCardCtx(ctx context.Context, card *LarkCard) error {
+	return lc.SendLarkNotificationCtx(ctx, &LarkMessage{MsgType: "interactive", Card: card})
+}
+
+// SendLarkNotification with json structure, signed when a secret is configured
+func (lc *LarkClient) SendLarkNotification(larkMessage *LarkMessage) error {
+	return lc.SendLarkNotificationCtx(context.Background(), larkMessage)
+}
+
+// SendLarkNotificationCtx is SendLarkNotification canceled with the context
+func (lc *LarkClient) SendLarkNotificationCtx(ctx context.Context, larkMessage *LarkMessage) error {
+	if lc.Secret != "" {
+		larkMessage.Timestamp = strconv.FormatInt(time.Now().Unix(), 10)
+		// the string to sign is the hmac key, the signed data is empty
+		mac := hmac.New(sha256.New, []byte(larkMessage.Timestamp+"\n"+lc.Secret))
+		larkMessage.Sign = base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	}
+	body, err := json.Marshal(larkMessage)
+	if err != nil {
+		return err
+	}
+	req, err := retryablehttp.NewRequest(http.MethodPost, lc.WebHookURL, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/json")
+
+	resp, err := doTimeout(lc.client, req.WithContext(ctx), lc.TimeOut)
+	if err != nil {
+		return err
+	}
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck // silent fail
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newResponseError(resp, buf)
+	}
+	var response larkResponse
+	if err := json.Unmarshal(buf, &response); err != nil {
+		return err
+	}
+	if response.Code != 0 {
+		return fmt.Errorf("lark: %s (%d)", response.Msg, response.Code)
+	}
+	return nil
+}
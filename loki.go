@@ -0,0 +1,101 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// DefaultLokiTimeout to conclude operations
+const DefaultLokiTimeout = 5 * time.Second
+
+// LokiClient pushes notifications as log lines to Grafana Loki
+type LokiClient struct {
+	client *retryablehttp.Client
+	URL    string
+	// Labels identify the stream, defaults to source=notify
+	Labels   map[string]string
+	Username string
+	Password string
+	// TenantID is sent as X-Scope-OrgID for multi-tenant deployments
+	TenantID string
+	TimeOut  time.Duration
+}
+
+// LokiPushRequest json structure
+type LokiPushRequest struct {
+	Streams []LokiStream `json:"streams"`
+}
+
+// LokiStream is a set of log lines sharing the same labels
+type LokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// SendInfo to loki
+func (lc *LokiClient) SendInfo(message string) error {
+	return lc.SendInfoCtx(context.Background(), message)
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (lc *LokiClient) SendInfoCtx(ctx context.Context, message string) error {
+	labels := lc.Labels
+	if len(labels) == 0 {
+		labels = map[string]string{"source": "notify"}
+	}
+	return lc.PushCtx(ctx, &LokiPushRequest{Streams: []LokiStream{{
+		Stream: labels,
+		Values: [][2]string{{strconv.FormatInt(time.Now().UnixNano(), 10), message}},
+	}}})
+}
+
+// Push the streams to loki
+func (lc *LokiClient) Push(pushRequest *LokiPushRequest) error {
+	return lc.PushCtx(context.Background(), pushRequest)
+}
+
+// PushCtx is Push canceled with the context
+func (lc *LokiClient) PushCtx(ctx context.Context, pushRequest *LokiPushRequest) error {
+	body, err := json.Marshal(pushRequest)
+	if err != nil {
+		return err
+	}
+	URL := strings.TrimSuffix(lc.URL, "/")
+	if !strings.HasSuffix(URL, "/loki/api/v1/push") {
+		URL += "/loki/api/v1/push"
+	}
+	req, err := retryablehttp.NewRequest(http.MethodPost, URL, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/json")
+	if lc.Username != "" {
+		req.SetBasicAuth(lc.Username, lc.Password)
+	}
+	if lc.TenantID != "" {
+		req.Header.Add("X-Scope-OrgID", lc.TenantID)
+	}
+
+	resp, err := doTimeout(lc.client, req.WithContext(ctx), lc.TimeOut)
+	if err != nil {
+		return err
+	}
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck // silent fail
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return newResponseError(resp, buf)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// DefaultMatrixTimeout to conclude operations
+const DefaultMatrixTimeout = 5 * time.Second
+
+// Matrix message types
+const (
+	MatrixMsgTypeText   = "m.text"
+	MatrixMsgTypeNotice = "m.notice"
+)
+
+// matrixHTMLFormat is the format of html formatted bodies
+const matrixHTMLFormat = "org.matrix.custom.html"
+
+// matrixTxnCounter makes the transaction ids unique within the process
+var matrixTxnCounter int64
+
+// MatrixClient sends messages to a room with the client-server api
+type MatrixClient struct {
+	client *retryablehttp.Client
+	// HomeserverURL as https://matrix.org
+	HomeserverURL string
+	AccessToken   string
+	// RoomID as !room:matrix.org
+	RoomID string
+	// MsgType defaults to MatrixMsgTypeText
+	MsgType string
+	// HTML sends the messages as html formatted bodies
+	HTML    bool
+	TimeOut time.Duration
+}
+
+// MatrixMessage json structure of a m.room.message event
+type MatrixMessage struct {
+	MsgType       string `json:"msgtype"`
+	Body          string `json:"body"`
+	Format        string `json:"format,omitempty"`
+	FormattedBody string `json:"formatted_body,omitempty"`
+}
+
+// matrixTagReplacer strips the common tags of html bodies for the plain text fallback
+var matrixTagReplacer = strings.NewReplacer("<br>", "\n", "<br/>", "\n", "<p>", "", "</p>", "\n", "<b>", "", "</b>", "",
+	"<strong>", "", "</strong>", "", "<i>", "", "</i>", "", "<em>", "", "</em>", "", "<code>", "", "</code>", "",
+	"<pre>", "", "</pre>", "")
+
+// SendInfo to matrix
+func (mc *MatrixClient) SendInfo(message string) error {
+	return mc.SendInfoCtx(context.Background(), message)
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (mc *MatrixClient) SendInfoCtx(ctx context.Context, message string) error {
+	if mc.HTML {
+		return mc.SendHTMLCtx(ctx, matrixTagReplacer.Replace(message), message)
+	}
+	return mc.SendMatrixNotificationCtx(ctx, &MatrixMessage{MsgType: mc.msgType(), Body: message})
+}
+
+// SendHTML sends the html body with the plain text fallback for clients without html support
+func (mc *MatrixClient) SendHTML(text, html string) error {
+	return mc.SendHTMLCtx(context.Background(), text, html)
+}
+
+// SendHTMLCtx is SendHTML canceled with the context
+func (mc *MatrixClient) SendHTMLCtx(ctx context.Context, text, html string) error {
+	return mc.SendMatrixNotificationCtx(ctx, &MatrixMessage{
+		MsgType:       mc.msgType(),
+		Body:          text,
+		Format:        matrixHTMLFormat,
+		FormattedBody: html,
+	})
+}
+
+func (mc *MatrixClient) msgType() string {
+	if mc.MsgType != "" {
+		return mc.MsgType
+	}
+	return MatrixMsgTypeText
+}
+
+// SendMatrixNotification with json structure
+func (mc *MatrixClient) SendMatrixNotification(matrixMessage *MatrixMessage) error {
+	return mc.SendMatrixNotificationCtx(context.Background(), matrixMessage)
+}
+
+// SendMatrixNotificationCtx is SendMatrixNotification canceled with the context
+func (mc *MatrixClient) SendMatrixNotificationCtx(ctx context.Context, matrixMessage *MatrixMessage) error {
+	body, err := json.Marshal(matrixMessage)
+	if err != nil {
+		return err
+	}
+	// the transaction id makes retries of the same request idempotent
+	txnID := strconv.FormatInt(time.Now().UnixNano(), 10) + "." + strconv.FormatInt(atomic.AddInt64(&matrixTxnCounter, 1), 10)
+	URL := strings.TrimSuffix(mc.HomeserverURL, "/") + "/_matrix/client/v3/rooms/" + url.PathEscape(mc.RoomID) + "/send/m.room.message/" + txnID
+	req, err := retryablehttp.NewRequest(http.MethodPut, URL, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Authorization", "Bearer "+mc.AccessToken)
+
+	resp, err := doTimeout(mc.client, req.WithContext(ctx), mc.TimeOut)
+	if err != nil {
+		return err
+	}
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck // silent fail
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newResponseError(resp, buf)
+	}
+	return nil
+}
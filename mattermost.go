@@ -0,0 +1,145 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// DefaultMattermostTimeout to conclude operations
+const DefaultMattermostTimeout = 5 * time.Second
+
+// MattermostClient handling mattermost incoming webhooks
+type MattermostClient struct {
+	client     *retryablehttp.Client
+	WebHookURL string
+	// Channel overrides the webhook channel when allowed by the server
+	Channel   string
+	UserName  string
+	IconURL   string
+	IconEmoji string
+	// Severities overrides the colors and labels of the severity helpers
+	Severities SeverityScheme
+	TimeOut    time.Duration
+}
+
+// MattermostMessage json structure, it's slack compatible
+// with the mattermost specific type and props
+type MattermostMessage struct {
+	Text        string                 `json:"text,omitempty"`
+	Channel     string                 `json:"channel,omitempty"`
+	Username    string                 `json:"username,omitempty"`
+	IconURL     string                 `json:"icon_url,omitempty"`
+	IconEmoji   string                 `json:"icon_emoji,omitempty"`
+	Attachments []MattermostAttachment `json:"attachments,omitempty"`
+	Type        string                 `json:"type,omitempty"`
+	Props       *MattermostProps       `json:"props,omitempty"`
+}
+
+// MattermostProps of a message
+type MattermostProps struct {
+	// Card is markdown shown in the right hand side panel of the post
+	Card string `json:"card,omitempty"`
+}
+
+// MattermostAttachment of mattermost message
+type MattermostAttachment struct {
+	Fallback   string                      `json:"fallback,omitempty"`
+	Color      string                      `json:"color,omitempty"`
+	Pretext    string                      `json:"pretext,omitempty"`
+	Text       string                      `json:"text,omitempty"`
+	AuthorName string                      `json:"author_name,omitempty"`
+	AuthorLink string                      `json:"author_link,omitempty"`
+	AuthorIcon string                      `json:"author_icon,omitempty"`
+	Title      string                      `json:"title,omitempty"`
+	TitleLink  string                      `json:"title_link,omitempty"`
+	Fields     []MattermostAttachmentField `json:"fields,omitempty"`
+	ImageURL   string                      `json:"image_url,omitempty"`
+	ThumbURL   string                      `json:"thumb_url,omitempty"`
+	Footer     string                      `json:"footer,omitempty"`
+	FooterIcon string                      `json:"footer_icon,omitempty"`
+}
+
+// MattermostAttachmentField is a table cell of an attachment
+type MattermostAttachmentField struct {
+	Title string `json:"title"`
+	// Value is a string or a number
+	Value interface{} `json:"value"`
+	Short bool        `json:"short"`
+}
+
+// SendInfo to mattermost
+func (mc *MattermostClient) SendInfo(message string) error {
+	return mc.SendInfoCtx(context.Background(), message)
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (mc *MattermostClient) SendInfoCtx(ctx context.Context, message string) error {
+	return mc.sendAttachment(ctx, SeverityInfo, message)
+}
+
+// SendWarning to mattermost
+func (mc *MattermostClient) SendWarning(message string) error {
+	return mc.sendAttachment(context.Background(), SeverityWarning, message)
+}
+
+// SendError to mattermost
+func (mc *MattermostClient) SendError(message string) error {
+	return mc.sendAttachment(context.Background(), SeverityError, message)
+}
+
+func (mc *MattermostClient) sendAttachment(ctx context.Context, severity, message string) error {
+	style := mc.Severities.Style(severity)
+	return mc.SendMattermostNotificationCtx(ctx, &MattermostMessage{
+		Attachments: []MattermostAttachment{{Fallback: message, Color: style.Color, Title: style.Label, Text: message}},
+	})
+}
+
+// SendMattermostNotification with json structure, the client channel,
+// username and icon are used when unset in the message
+func (mc *MattermostClient) SendMattermostNotification(mattermostMessage *MattermostMessage) error {
+	return mc.SendMattermostNotificationCtx(context.Background(), mattermostMessage)
+}
+
+// SendMattermostNotificationCtx is SendMattermostNotification canceled with the context
+func (mc *MattermostClient) SendMattermostNotificationCtx(ctx context.Context, mattermostMessage *MattermostMessage) error {
+	if mattermostMessage.Channel == "" {
+		mattermostMessage.Channel = mc.Channel
+	}
+	if mattermostMessage.Username == "" {
+		mattermostMessage.Username = mc.UserName
+	}
+	if mattermostMessage.IconURL == "" && mattermostMessage.IconEmoji == "" {
+		mattermostMessage.IconURL = mc.IconURL
+		mattermostMessage.IconEmoji = mc.IconEmoji
+	}
+	body, err := json.Marshal(mattermostMessage)
+	if err != nil {
+		return err
+	}
+	req, err := retryablehttp.NewRequest(http.MethodPost, mc.WebHookURL, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/json")
+
+	resp, err := doTimeout(mc.client, req.WithContext(ctx), mc.TimeOut)
+	if err != nil {
+		return err
+	}
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck // silent fail
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newResponseError(resp, buf)
+	}
+	return nil
+}
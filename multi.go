@@ -0,0 +1,123 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MultiNotifier sends each message to several notifiers concurrently
+type MultiNotifier struct {
+	names     []string
+	notifiers map[string]Notifier
+}
+
+// NewMultiNotifier returns an empty fan-out notifier
+func NewMultiNotifier() *MultiNotifier {
+	return &MultiNotifier{notifiers: make(map[string]Notifier)}
+}
+
+// Add a notifier by name, adding a name twice replaces the notifier
+func (m *MultiNotifier) Add(name string, notifier Notifier) *MultiNotifier {
+	if _, ok := m.notifiers[name]; !ok {
+		m.names = append(m.names, name)
+	}
+	m.notifiers[name] = notifier
+	return m
+}
+
+// Send the message to every notifier, a *MultiError describes the outcome
+// when at least one of them failed
+func (m *MultiNotifier) Send(ctx context.Context, message *Message) error {
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	multiErr := &MultiError{}
+	for _, name := range m.names {
+		wg.Add(1)
+		go func(name string, notifier Notifier) {
+			defer wg.Done()
+			err := notifier.Send(ctx, message)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err != nil {
+				multiErr.Failed = append(multiErr.Failed, withProvider(name, err))
+				return
+			}
+			multiErr.Succeeded = append(multiErr.Succeeded, name)
+		}(name, m.notifiers[name])
+	}
+	wg.Wait()
+
+	if len(multiErr.Failed) == 0 {
+		return nil
+	}
+	sort.Strings(multiErr.Succeeded)
+	sort.Slice(multiErr.Failed, func(i, j int) bool {
+		return multiErr.Failed[i].Error() < multiErr.Failed[j].Error()
+	})
+	return multiErr
+}
+
+// Close closes the notifiers having a Close method
+func (m *MultiNotifier) Close() {
+	for _, name := range m.names {
+		switch notifier := m.notifiers[name].(type) {
+		case interface{ Close() }:
+			notifier.Close()
+		case interface{ Close() error }:
+			//nolint:errcheck // silent fail
+			notifier.Close()
+		}
+	}
+}
+
+// MultiError reports the providers of a fan-out which succeeded and failed
+type MultiError struct {
+	Succeeded []string
+	// Failed errors are attributed to their provider with a ProviderError
+	Failed []error
+}
+
+// Error lists the failures
+func (e *MultiError) Error() string {
+	failures := make([]string, len(e.Failed))
+	for i, err := range e.Failed {
+		failures[i] = err.Error()
+	}
+	total := len(e.Succeeded) + len(e.Failed)
+	return strconv.Itoa(len(e.Failed)) + " of " + strconv.Itoa(total) + " providers failed: " + strings.Join(failures, "; ")
+}
+
+// Is reports whether any of the failures matches the target
+func (e *MultiError) Is(target error) bool {
+	for _, err := range e.Failed {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// MultiNotifier returns a fan-out notifier of the enabled providers and
+// registered notifiers by name, all of them when no name is given
+func (n *Notify) MultiNotifier(names ...string) (*MultiNotifier, error) {
+	if len(names) == 0 {
+		for _, p := range n.enabledProviders() {
+			names = append(names, p.name)
+		}
+	}
+	multi := NewMultiNotifier()
+	for _, name := range names {
+		notifier, ok := n.Notifier(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown notifier %q", name)
+		}
+		multi.Add(name, notifier)
+	}
+	return multi, nil
+}
@@ -0,0 +1,380 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"crypto/ed25519"
+	"crypto/tls"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultNATSTimeout to conclude operations
+const DefaultNATSTimeout = 10 * time.Second
+
+// natsMaxLineLength bounds the protocol lines read from the server
+const natsMaxLineLength = 64 << 10
+
+var (
+	// ErrNATSCredentials is returned for credentials files without jwt or user seed
+	ErrNATSCredentials = errors.New("nats: invalid credentials file")
+
+	natsCredsRegex = regexp.MustCompile(`(?m)^\s*-{3,}\s*BEGIN [^\n]*?(JWT|SEED)\s*-{3,}\s*$\n\s*(\S+)`)
+)
+
+// NATSClient publishes notifications to a subject, optionally through jetstream
+type NATSClient struct {
+	// URL of the server as nats://host:port, tls:// connects with tls
+	URL     string
+	Subject string
+	Token   string
+	// CredentialsFile is a .creds file holding the user jwt and nkey seed
+	CredentialsFile string
+	TLS             bool
+	// JetStream publishes to a stream and waits for its acknowledgement
+	JetStream bool
+	TimeOut   time.Duration
+
+	capture captureFunc
+}
+
+// natsInfo is the relevant part of the server INFO
+type natsInfo struct {
+	TLSRequired bool   `json:"tls_required"`
+	Nonce       string `json:"nonce"`
+}
+
+// natsConnect options of the CONNECT message
+type natsConnect struct {
+	Verbose   bool   `json:"verbose"`
+	Pedantic  bool   `json:"pedantic"`
+	Name      string `json:"name"`
+	Lang      string `json:"lang"`
+	Protocol  int    `json:"protocol"`
+	AuthToken string `json:"auth_token,omitempty"`
+	JWT       string `json:"jwt,omitempty"`
+	Signature string `json:"sig,omitempty"`
+}
+
+// NATSPubAck is the jetstream acknowledgement of a published message
+type NATSPubAck struct {
+	Stream   string `json:"stream"`
+	Sequence uint64 `json:"seq"`
+	Error    *struct {
+		Code        int    `json:"code"`
+		Description string `json:"description"`
+	} `json:"error,omitempty"`
+}
+
+// natsConn is a connected server session
+type natsConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// SendInfo to nats
+func (nc *NATSClient) SendInfo(message string) error {
+	return nc.SendInfoCtx(context.Background(), message)
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (nc *NATSClient) SendInfoCtx(ctx context.Context, message string) error {
+	return nc.SendNATSNotificationCtx(ctx, message)
+}
+
+// SendNATSNotification publishes the message to the subject
+func (nc *NATSClient) SendNATSNotification(message string) error {
+	return nc.SendNATSNotificationCtx(context.Background(), message)
+}
+
+// SendNATSNotificationCtx is SendNATSNotification canceled with the context
+func (nc *NATSClient) SendNATSNotificationCtx(ctx context.Context, message string) error {
+	if nc.capture != nil {
+		target := nc.Subject
+		if u, err := url.Parse(nc.URL); err == nil {
+			target = redactURL(u) + "/" + nc.Subject
+		}
+		nc.capture(target, []byte(message))
+		return nil
+	}
+
+	c, err := nc.connect(ctx)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck // silent fail
+	defer c.conn.Close()
+
+	if !nc.JetStream {
+		if err := c.write(fmt.Sprintf("PUB %s %d\r\n%s\r\n", nc.Subject, len(message), message)); err != nil {
+			return err
+		}
+		// the server answers the ping once the message is processed
+		return c.ping()
+	}
+
+	inbox := fmt.Sprintf("_INBOX.notify.%d", time.Now().UnixNano())
+	if err := c.write(fmt.Sprintf("SUB %s 1\r\nPUB %s %s %d\r\n%s\r\n", inbox, nc.Subject, inbox, len(message), message)); err != nil {
+		return err
+	}
+	payload, err := c.waitMsg()
+	if err != nil {
+		return err
+	}
+	var ack NATSPubAck
+	if err := json.Unmarshal(payload, &ack); err != nil {
+		return fmt.Errorf("nats: unexpected jetstream reply: %s", payload)
+	}
+	if ack.Error != nil {
+		return fmt.Errorf("nats: jetstream: %s (%d)", ack.Error.Description, ack.Error.Code)
+	}
+	return nil
+}
+
+func (nc *NATSClient) connect(ctx context.Context) (*natsConn, error) {
+	timeout := nc.TimeOut
+	if timeout == 0 {
+		timeout = DefaultNATSTimeout
+	}
+	addr, useTLS, err := natsAddress(nc.URL)
+	if err != nil {
+		return nil, err
+	}
+	useTLS = useTLS || nc.TLS
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	deadline := socketDeadline(ctx, timeout)
+	dialer := &net.Dialer{Timeout: timeout, Deadline: deadline}
+	conn, err := dialer.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	//nolint:errcheck // bounds the whole exchange
+	conn.SetDeadline(deadline)
+	c := &natsConn{conn: conn, reader: bufio.NewReader(conn)}
+	if err := nc.handshake(c, addr, useTLS, deadline); err != nil {
+		//nolint:errcheck // silent fail
+		c.conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// handshake reads the server info, upgrades to tls and authenticates
+func (nc *NATSClient) handshake(c *natsConn, addr string, useTLS bool, deadline time.Time) error {
+	line, err := c.readLine()
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(line, "INFO ") {
+		return fmt.Errorf("nats: unexpected greeting: %s", line)
+	}
+	var info natsInfo
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "INFO ")), &info); err != nil {
+		return err
+	}
+
+	if useTLS || info.TLSRequired {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			return err
+		}
+		tlsConn := tls.Client(c.conn, &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12})
+		//nolint:errcheck // bounds the whole exchange
+		tlsConn.SetDeadline(deadline)
+		if err := tlsConn.Handshake(); err != nil {
+			return err
+		}
+		c.conn = tlsConn
+		c.reader = bufio.NewReader(tlsConn)
+	}
+
+	connect := natsConnect{Name: "notify", Lang: "go", Protocol: 1, AuthToken: nc.Token}
+	if nc.CredentialsFile != "" {
+		jwt, seed, err := readNATSCredentials(nc.CredentialsFile)
+		if err != nil {
+			return err
+		}
+		key, err := decodeNATSSeed(seed)
+		if err != nil {
+			return err
+		}
+		connect.JWT = jwt
+		connect.Signature = base64.RawURLEncoding.EncodeToString(ed25519.Sign(key, []byte(info.Nonce)))
+	}
+	body, err := json.Marshal(connect)
+	if err != nil {
+		return err
+	}
+	if err := c.write("CONNECT " + string(body) + "\r\n"); err != nil {
+		return err
+	}
+	return c.ping()
+}
+
+// ping waits for the pong, reporting the errors sent by the server before it
+func (c *natsConn) ping() error {
+	if err := c.write("PING\r\n"); err != nil {
+		return err
+	}
+	for {
+		line, err := c.readControl()
+		if err != nil {
+			return err
+		}
+		if line == "PONG" {
+			return nil
+		}
+	}
+}
+
+// waitMsg returns the payload of the next message delivered to the subscription
+func (c *natsConn) waitMsg() ([]byte, error) {
+	for {
+		line, err := c.readControl()
+		if err != nil {
+			return nil, err
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 4 || fields[0] != "MSG" {
+			continue
+		}
+		size, err := strconv.Atoi(fields[len(fields)-1])
+		if err != nil || size < 0 || size > natsMaxLineLength {
+			return nil, fmt.Errorf("nats: invalid message: %s", line)
+		}
+		payload := make([]byte, size+2)
+		if _, err := io.ReadFull(c.reader, payload); err != nil {
+			return nil, err
+		}
+		return payload[:size], nil
+	}
+}
+
+// readControl reads protocol lines answering pings, -ERR lines are returned as errors
+func (c *natsConn) readControl() (string, error) {
+	for {
+		line, err := c.readLine()
+		if err != nil {
+			return "", err
+		}
+		switch {
+		case line == "PING":
+			if err := c.write("PONG\r\n"); err != nil {
+				return "", err
+			}
+		case line == "+OK", strings.HasPrefix(line, "INFO "):
+		case strings.HasPrefix(line, "-ERR"):
+			return "", fmt.Errorf("nats: %s", strings.Trim(strings.TrimPrefix(line, "-ERR"), " '"))
+		default:
+			return line, nil
+		}
+	}
+}
+
+func (c *natsConn) readLine() (string, error) {
+	var line []byte
+	for {
+		chunk, isPrefix, err := c.reader.ReadLine()
+		if err != nil {
+			return "", err
+		}
+		line = append(line, chunk...)
+		if len(line) > natsMaxLineLength {
+			return "", errors.New("nats: protocol line too long")
+		}
+		if !isPrefix {
+			return string(line), nil
+		}
+	}
+}
+
+func (c *natsConn) write(data string) error {
+	_, err := io.WriteString(c.conn, data)
+	return err
+}
+
+// natsAddress returns the host:port of the url and whether tls is requested
+func natsAddress(rawURL string) (string, bool, error) {
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "nats://" + rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", false, err
+	}
+	port := u.Port()
+	if port == "" {
+		port = "4222"
+	}
+	return net.JoinHostPort(u.Hostname(), port), u.Scheme == "tls", nil
+}
+
+// readNATSCredentials extracts the user jwt and nkey seed of a .creds file
+func readNATSCredentials(path string) (string, string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", "", err
+	}
+	var jwt, seed string
+	for _, match := range natsCredsRegex.FindAllStringSubmatch(string(data), -1) {
+		if match[1] == "JWT" {
+			jwt = match[2]
+		} else {
+			seed = match[2]
+		}
+	}
+	if jwt == "" || seed == "" {
+		return "", "", ErrNATSCredentials
+	}
+	return jwt, seed, nil
+}
+
+// decodeNATSSeed decodes an nkey user seed, the base32 encoding of the prefix
+// bytes, the ed25519 seed and a crc16 checksum
+func decodeNATSSeed(seed string) (ed25519.PrivateKey, error) {
+	raw, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(seed)
+	if err != nil || len(raw) != 2+ed25519.SeedSize+2 {
+		return nil, ErrNATSCredentials
+	}
+	data, checksum := raw[:len(raw)-2], binary.LittleEndian.Uint16(raw[len(raw)-2:])
+	if natsCRC16(data) != checksum {
+		return nil, ErrNATSCredentials
+	}
+	// seed prefix followed by the user key prefix
+	const seedPrefix, userPrefix = 18 << 3, 20 << 3
+	if data[0]&0xf8 != seedPrefix || (data[0]&7)<<5|(data[1]&0xf8)>>3 != userPrefix {
+		return nil, ErrNATSCredentials
+	}
+	return ed25519.NewKeyFromSeed(data[2:]), nil
+}
+
+// natsCRC16 is the crc16 xmodem checksum of nkeys
+func natsCRC16(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
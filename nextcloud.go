@@ -0,0 +1,92 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// DefaultNextcloudTalkTimeout to conclude operations
+const DefaultNextcloudTalkTimeout = 5 * time.Second
+
+// nextcloudTalkMaxMessage is the longest chat message accepted by talk
+const nextcloudTalkMaxMessage = 32000
+
+// NextcloudTalkClient posts messages to a talk room through the ocs api
+type NextcloudTalkClient struct {
+	client *retryablehttp.Client
+	// URL of the nextcloud server, eg. https://cloud.example.com
+	URL string
+	// Username and AppPassword authenticate, app passwords are created in the security settings
+	Username    string
+	AppPassword string
+	// RoomToken is the token in the url of the conversation
+	RoomToken string
+	TimeOut   time.Duration
+}
+
+// NextcloudTalkMessage json structure
+type NextcloudTalkMessage struct {
+	Message          string `json:"message"`
+	ReplyTo          int    `json:"replyTo,omitempty"`
+	ActorDisplayName string `json:"actorDisplayName,omitempty"`
+}
+
+// SendInfo to nextcloud talk
+func (nc *NextcloudTalkClient) SendInfo(message string) error {
+	return nc.SendInfoCtx(context.Background(), message)
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (nc *NextcloudTalkClient) SendInfoCtx(ctx context.Context, message string) error {
+	for _, chunk := range splitMessage(message, nextcloudTalkMaxMessage) {
+		if err := nc.SendNextcloudTalkNotificationCtx(ctx, &NextcloudTalkMessage{Message: chunk}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SendNextcloudTalkNotification with json structure
+func (nc *NextcloudTalkClient) SendNextcloudTalkNotification(talkMessage *NextcloudTalkMessage) error {
+	return nc.SendNextcloudTalkNotificationCtx(context.Background(), talkMessage)
+}
+
+// SendNextcloudTalkNotificationCtx is SendNextcloudTalkNotification canceled with the context
+func (nc *NextcloudTalkClient) SendNextcloudTalkNotificationCtx(ctx context.Context, talkMessage *NextcloudTalkMessage) error {
+	body, err := json.Marshal(talkMessage)
+	if err != nil {
+		return err
+	}
+	URL := strings.TrimSuffix(nc.URL, "/") + "/ocs/v2.php/apps/spreed/api/v1/chat/" + url.PathEscape(nc.RoomToken)
+	req, err := retryablehttp.NewRequest(http.MethodPost, URL, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("OCS-APIRequest", "true")
+	req.SetBasicAuth(nc.Username, nc.AppPassword)
+
+	resp, err := doTimeout(nc.client, req.WithContext(ctx), nc.TimeOut)
+	if err != nil {
+		return err
+	}
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck // silent fail
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return newResponseError(resp, buf)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Message is a notification delivered by a Notifier
+type Message struct {
+	Title string
+	Text  string
+}
+
+// String renders the message as plain text for text only providers
+func (m *Message) String() string {
+	if m.Title == "" {
+		return m.Text
+	}
+	return m.Title + "\n" + m.Text
+}
+
+// Notifier delivers messages to a provider
+type Notifier interface {
+	Send(ctx context.Context, message *Message) error
+}
+
+// NotifierFunc adapts a function to the Notifier interface
+type NotifierFunc func(ctx context.Context, message *Message) error
+
+// Send calls f(ctx, message)
+func (f NotifierFunc) Send(ctx context.Context, message *Message) error {
+	return f(ctx, message)
+}
+
+// NotifierFactory creates a registered notifier from the options
+type NotifierFactory func(options *Options) (Notifier, error)
+
+var registry = struct {
+	sync.RWMutex
+	factories map[string]NotifierFactory
+}{factories: make(map[string]NotifierFactory)}
+
+// Register makes a notifier available by name, it is enabled by listing
+// the name in Options.Notifiers. Register panics if the name is registered
+// twice.
+func Register(name string, factory NotifierFactory) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	if factory == nil {
+		panic("notify: Register factory is nil")
+	}
+	if _, dup := registry.factories[name]; dup {
+		panic("notify: Register called twice for notifier " + name)
+	}
+	registry.factories[name] = factory
+}
+
+// Lookup returns the factory of a registered notifier
+func Lookup(name string) (NotifierFactory, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	factory, ok := registry.factories[name]
+	return factory, ok
+}
+
+// Registered returns the sorted names of the registered notifiers
+func Registered() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	names := make([]string, 0, len(registry.factories))
+	for name := range registry.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// newNotifiers creates the registered notifiers enabled in the options
+func newNotifiers(options *Options) (map[string]Notifier, error) {
+	notifiers := make(map[string]Notifier, len(options.Notifiers))
+	for _, name := range options.Notifiers {
+		factory, ok := Lookup(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown notifier %q", name)
+		}
+		notifier, err := factory(options)
+		if err != nil {
+			return nil, withProvider(name, err)
+		}
+		notifiers[name] = notifier
+	}
+	return notifiers, nil
+}
+
+// Notifier returns the enabled provider or registered notifier by name
+func (n *Notify) Notifier(name string) (Notifier, bool) {
+	if notifier, ok := n.notifiers[name]; ok {
+		return notifier, true
+	}
+	for _, p := range n.enabledProviders() {
+		if p.name == name {
+			p := p
+			limiter := n.limiters[name]
+			return NotifierFunc(func(ctx context.Context, message *Message) error {
+				if limiter != nil {
+					if err := limiter.wait(ctx); err != nil {
+						return err
+					}
+				}
+				switch {
+				case p.sendResult != nil:
+					_, err := p.sendResult(ctx, message.String())
+					return err
+				case p.sendCtx != nil:
+					return p.sendCtx(ctx, message.String())
+				default:
+					return p.send(message.String())
+				}
+			}), true
+		}
+	}
+	return nil, false
+}
@@ -1,23 +1,94 @@
 package notify
 
 import (
+	"context"
+	"net/http"
+	"runtime/pprof"
+
 	"github.com/acarl005/stripansi"
 	"github.com/projectdiscovery/retryablehttp-go"
 )
 
 // Notify handles the notification engine
 type Notify struct {
-	options        *Options
-	client         *retryablehttp.Client
-	slackClient    *SlackClient
-	discordClient  *DiscordClient
-	telegramClient *TelegramClient
+	options             *Options
+	client              *retryablehttp.Client
+	slackClient         *SlackClient
+	discordClient       *DiscordClient
+	telegramClient      *TelegramClient
+	s3Client            *S3Client
+	esClient            *ElasticsearchClient
+	splunkClient        *SplunkClient
+	lokiClient          *LokiClient
+	clickHouseClient    *ClickHouseClient
+	grafanaClient       *GrafanaClient
+	influxDBClient      *InfluxDBClient
+	chimeClient         *ChimeClient
+	bitrix24Client      *Bitrix24Client
+	teamsClient         *TeamsClient
+	cloudEventsClient   *CloudEventsClient
+	mattermostClient    *MattermostClient
+	googleChatClient    *GoogleChatClient
+	pushoverClient      *PushoverClient
+	gotifyClient        *GotifyClient
+	ntfyClient          *NtfyClient
+	matrixClient        *MatrixClient
+	signalClient        *SignalClient
+	snsClient           *SNSClient
+	emailClient         *EmailClient
+	pagerDutyClient     *PagerDutyClient
+	opsgenieClient      *OpsgenieClient
+	zulipClient         *ZulipClient
+	xmppClient          *XMPPClient
+	ircClient           *IRCClient
+	dingTalkClient      *DingTalkClient
+	weComClient         *WeComClient
+	larkClient          *LarkClient
+	barkClient          *BarkClient
+	webexClient         *WebexClient
+	jiraClient          *JiraClient
+	gitHubClient        *GitHubClient
+	gitLabClient        *GitLabClient
+	kafkaClient         *KafkaClient
+	natsClient          *NATSClient
+	datadogClient       *DatadogClient
+	sentryClient        *SentryClient
+	customWebhookClient *CustomWebhookClient
+	sesClient           *SESClient
+	nextcloudTalkClient *NextcloudTalkClient
+	serviceURLClient    *ServiceURLClient
+	stats               *statsCollector
+	events              *eventBus
+	captures            *captureStore
+	health              *healthTracker
+	coalescer           *coalescer
+	approvals           *approvals
+	queue               *asyncQueue
+	notifiers           map[string]Notifier
+	limiters            map[string]*tokenBucket
+	templates           *messageTemplates
+	circuits            *circuits
+	flusher             *batchFlusher
+}
+
+// provider is a webhook enabled in the options
+type provider struct {
+	name string
+	send func(message string) error
+	// sendCtx is used over send to cancel in-flight requests
+	sendCtx func(ctx context.Context, message string) error
+	// sendResult describes the delivery on providers returning message ids or status codes
+	sendResult func(ctx context.Context, message string) (*DeliveryResult, error)
+	// sendConfirmed delivers and confirms the message when confirmation is enabled
+	sendConfirmed func(ctx context.Context, message string) (*DeliveryResult, error)
+	// flush writes buffered messages of batching sinks
+	flush func() error
 }
 
 // New notify instance
 func New() (*Notify, error) {
 	retryhttp := retryablehttp.NewClient(retryablehttp.DefaultOptionsSingle)
-	return &Notify{client: retryhttp}, nil
+	return &Notify{client: retryhttp, stats: newStatsCollector(), events: newEventBus(), captures: &captureStore{}, health: newHealthTracker(), approvals: newApprovals(), circuits: newCircuits(), queue: newAsyncQueue(DefaultQueueSize, DefaultQueueWorkers, 0)}, nil
 }
 
 // NewWithOptions create a new instance of notify with options
@@ -26,51 +97,810 @@ func NewWithOptions(options *Options) (*Notify, error) {
 	if err != nil {
 		return nil, err
 	}
-	SlackClient := &SlackClient{
-		client:     notifier.client,
-		WebHookURL: options.SlackWebHookURL,
-		UserName:   options.SlackUsername,
-		Channel:    options.SlackUsername,
-		TimeOut:    DefaultSlackTimeout,
-	}
-	discordClient := &DiscordClient{
-		client:     notifier.client,
-		WebHookURL: options.DiscordWebHookURL,
-		UserName:   options.DiscordWebHookUsername,
-		Avatar:     options.DiscordWebHookAvatarURL,
-	}
-	telegramClient := &TelegramClient{
-		client: notifier.client,
-		apiKEY: options.TelegramAPIKey,
-		chatID: options.TelegramChatID,
-	}
-	return &Notify{options: options, slackClient: SlackClient, discordClient: discordClient, telegramClient: telegramClient}, nil
+	notifier.options = options
+	if err := validateProxies(options); err != nil {
+		return nil, err
+	}
+	if err := validateRetries(options); err != nil {
+		return nil, err
+	}
+	var restored []queuedMessage
+	var wal *queueWAL
+	if options.QueuePath != "" {
+		if wal, restored, err = openQueueWAL(options.QueuePath); err != nil {
+			return nil, err
+		}
+	}
+	size := options.QueueSize
+	if size <= 0 {
+		size = DefaultQueueSize
+	}
+	if len(restored) > size {
+		size = len(restored)
+	}
+	notifier.limiters = newRateLimiters(options.RateLimits)
+	if notifier.templates, err = parseTemplates(options.Templates); err != nil {
+		return nil, err
+	}
+	notifier.queue = newAsyncQueue(size, options.QueueWorkers, options.QueueMaxBytes)
+	notifier.queue.wal = wal
+	if notifier.notifiers, err = newNotifiers(options); err != nil {
+		return nil, err
+	}
+	if options.CoalesceWindow > 0 {
+		notifier.coalescer = newCoalescer(options.CoalesceWindow, options.CoalesceMaxMessages, notifier.enqueue)
+	}
+	notifier.slackClient = &SlackClient{
+		client:          retryRateLimits(notifier.newProviderClient(ProviderSlack)),
+		WebHookURL:      options.SlackWebHookURL,
+		WebHookURLs:     options.SlackWebHookURLs,
+		WebHookStrategy: WebhookStrategy(options.SlackWebHookStrategy),
+		Token:           options.SlackToken,
+		UserName:        options.SlackUsername,
+		Channel:         options.SlackChannel,
+		IconURL:         options.SlackIconURL,
+		Mode:            SlackMode(options.SlackMode),
+		ThreadTS:        options.SlackThreadTS,
+		DisableUnfurl:   options.SlackDisableUnfurl,
+		Severities:      options.SeverityScheme,
+		TimeOut:         DefaultSlackTimeout,
+	}
+	notifier.discordClient = &DiscordClient{
+		client:          notifier.newProviderClient(ProviderDiscord),
+		WebHookURL:      options.DiscordWebHookURL,
+		WebHookURLs:     options.DiscordWebHookURLs,
+		WebHookStrategy: WebhookStrategy(options.DiscordWebHookStrategy),
+		UserName:        options.DiscordWebHookUsername,
+		Avatar:          options.DiscordWebHookAvatarURL,
+		CriticalRoles:   options.DiscordCriticalRoles,
+		Severities:      options.SeverityScheme,
+		TimeOut:         DefaultDiscordTimeout,
+	}
+	notifier.telegramClient = &TelegramClient{
+		client:    notifier.newProviderClient(ProviderTelegram),
+		apiKEY:    options.TelegramAPIKey,
+		chatID:    options.TelegramChatID,
+		ParseMode: TelegramParseMode(options.TelegramParseMode),
+		TimeOut:   DefaultTelegraTimeout,
+	}
+	notifier.s3Client = &S3Client{
+		client:      notifier.newProviderClient(ProviderS3),
+		Endpoint:    options.S3Endpoint,
+		Region:      options.S3Region,
+		Bucket:      options.S3Bucket,
+		Prefix:      options.S3Prefix,
+		Credentials: AWSCredentials{AccessKeyID: options.S3AccessKeyID, SecretAccessKey: options.S3SecretAccessKey},
+		PathStyle:   options.S3PathStyle,
+		BatchSize:   options.S3BatchSize,
+		TimeOut:     DefaultS3Timeout,
+	}
+	notifier.esClient = &ElasticsearchClient{
+		client:    notifier.newProviderClient(ProviderElasticsearch),
+		URL:       options.ElasticsearchURL,
+		Index:     options.ElasticsearchIndex,
+		Username:  options.ElasticsearchUsername,
+		Password:  options.ElasticsearchPassword,
+		APIKey:    options.ElasticsearchAPIKey,
+		Source:    options.ElasticsearchSource,
+		Template:  options.ElasticsearchTemplate,
+		BatchSize: options.ElasticsearchBatchSize,
+		TimeOut:   DefaultElasticsearchTimeout,
+	}
+	notifier.splunkClient = &SplunkClient{
+		client:     notifier.newProviderClient(ProviderSplunk),
+		URL:        options.SplunkURL,
+		Token:      options.SplunkToken,
+		SourceType: options.SplunkSourceType,
+		Index:      options.SplunkIndex,
+		Source:     options.SplunkSource,
+		Host:       options.SplunkHost,
+		BatchSize:  options.SplunkBatchSize,
+		TimeOut:    DefaultSplunkTimeout,
+	}
+	notifier.lokiClient = &LokiClient{
+		client:   notifier.newProviderClient(ProviderLoki),
+		URL:      options.LokiURL,
+		Labels:   options.LokiLabels,
+		Username: options.LokiUsername,
+		Password: options.LokiPassword,
+		TenantID: options.LokiTenantID,
+		TimeOut:  DefaultLokiTimeout,
+	}
+	notifier.clickHouseClient = &ClickHouseClient{
+		client:      notifier.newProviderClient(ProviderClickHouse),
+		URL:         options.ClickHouseURL,
+		Database:    options.ClickHouseDatabase,
+		Table:       options.ClickHouseTable,
+		Username:    options.ClickHouseUsername,
+		Password:    options.ClickHousePassword,
+		CreateTable: options.ClickHouseCreateTable,
+		BatchSize:   options.ClickHouseBatchSize,
+		TimeOut:     DefaultClickHouseTimeout,
+	}
+	notifier.grafanaClient = &GrafanaClient{
+		client:       notifier.newProviderClient(ProviderGrafana),
+		URL:          options.GrafanaURL,
+		APIKey:       options.GrafanaAPIKey,
+		DashboardUID: options.GrafanaDashboardUID,
+		PanelID:      options.GrafanaPanelID,
+		Tags:         options.GrafanaTags,
+		TimeOut:      DefaultGrafanaTimeout,
+	}
+	notifier.influxDBClient = &InfluxDBClient{
+		client:      notifier.newProviderClient(ProviderInfluxDB),
+		URL:         options.InfluxDBURL,
+		Token:       options.InfluxDBToken,
+		Org:         options.InfluxDBOrg,
+		Bucket:      options.InfluxDBBucket,
+		Measurement: options.InfluxDBMeasurement,
+		Tags:        options.InfluxDBTags,
+		TimeOut:     DefaultInfluxDBTimeout,
+	}
+	notifier.chimeClient = &ChimeClient{
+		client:     notifier.newProviderClient(ProviderChime),
+		WebHookURL: options.ChimeWebHookURL,
+		Markdown:   options.ChimeMarkdown,
+		Mention:    options.ChimeMention,
+		TimeOut:    DefaultChimeTimeout,
+	}
+	notifier.bitrix24Client = &Bitrix24Client{
+		client:     notifier.newProviderClient(ProviderBitrix24),
+		WebHookURL: options.Bitrix24WebHookURL,
+		DialogID:   options.Bitrix24DialogID,
+		BotID:      options.Bitrix24BotID,
+		ClientID:   options.Bitrix24ClientID,
+		TimeOut:    DefaultBitrix24Timeout,
+	}
+	notifier.teamsClient = &TeamsClient{
+		client:     notifier.newProviderClient(ProviderTeams),
+		WebHookURL: options.TeamsWebHookURL,
+		Mentions:   options.TeamsMentions,
+		CardFormat: TeamsCardFormat(options.TeamsCardFormat),
+		Severities: options.SeverityScheme,
+		TimeOut:    DefaultTeamsTimeout,
+	}
+	notifier.cloudEventsClient = &CloudEventsClient{
+		client:  notifier.newProviderClient(ProviderCloudEvents),
+		URL:     options.CloudEventsURL,
+		Source:  options.CloudEventsSource,
+		Type:    options.CloudEventsType,
+		Mode:    CloudEventsMode(options.CloudEventsMode),
+		TimeOut: DefaultCloudEventsTimeout,
+	}
+	notifier.mattermostClient = &MattermostClient{
+		client:     notifier.newProviderClient(ProviderMattermost),
+		WebHookURL: options.MattermostWebHookURL,
+		Channel:    options.MattermostChannel,
+		UserName:   options.MattermostUsername,
+		IconURL:    options.MattermostIconURL,
+		IconEmoji:  options.MattermostIconEmoji,
+		Severities: options.SeverityScheme,
+		TimeOut:    DefaultMattermostTimeout,
+	}
+	notifier.googleChatClient = &GoogleChatClient{
+		client:     notifier.newProviderClient(ProviderGoogleChat),
+		WebHookURL: options.GoogleChatWebHookURL,
+		ThreadKey:  options.GoogleChatThreadKey,
+		TimeOut:    DefaultGoogleChatTimeout,
+	}
+	notifier.pushoverClient = &PushoverClient{
+		client:   notifier.newProviderClient(ProviderPushover),
+		Token:    options.PushoverToken,
+		UserKey:  options.PushoverUserKey,
+		Device:   options.PushoverDevice,
+		Priority: options.PushoverPriority,
+		Sound:    options.PushoverSound,
+		TimeOut:  DefaultPushoverTimeout,
+	}
+	notifier.gotifyClient = &GotifyClient{
+		client:   notifier.newProviderClient(ProviderGotify),
+		URL:      options.GotifyURL,
+		Token:    options.GotifyToken,
+		Title:    options.GotifyTitle,
+		Markdown: options.GotifyMarkdown,
+		TimeOut:  DefaultGotifyTimeout,
+	}
+	notifier.ntfyClient = &NtfyClient{
+		client:   notifier.newProviderClient(ProviderNtfy),
+		URL:      options.NtfyURL,
+		Topic:    options.NtfyTopic,
+		Token:    options.NtfyToken,
+		Title:    options.NtfyTitle,
+		Priority: options.NtfyPriority,
+		Tags:     options.NtfyTags,
+		Click:    options.NtfyClick,
+		TimeOut:  DefaultNtfyTimeout,
+	}
+	notifier.matrixClient = &MatrixClient{
+		client:        notifier.newProviderClient(ProviderMatrix),
+		HomeserverURL: options.MatrixHomeserverURL,
+		AccessToken:   options.MatrixAccessToken,
+		RoomID:        options.MatrixRoomID,
+		MsgType:       options.MatrixMsgType,
+		HTML:          options.MatrixHTML,
+		TimeOut:       DefaultMatrixTimeout,
+	}
+	notifier.signalClient = &SignalClient{
+		client:     notifier.newProviderClient(ProviderSignal),
+		URL:        options.SignalURL,
+		Number:     options.SignalNumber,
+		Recipients: options.SignalRecipients,
+		TimeOut:    DefaultSignalTimeout,
+	}
+	notifier.snsClient = &SNSClient{
+		client:      notifier.newProviderClient(ProviderSNS),
+		TopicARN:    options.SNSTopicARN,
+		Region:      options.SNSRegion,
+		Credentials: AWSCredentials{AccessKeyID: options.SNSAccessKeyID, SecretAccessKey: options.SNSSecretAccessKey},
+		Subject:     options.SNSSubject,
+		TimeOut:     DefaultSNSTimeout,
+	}
+	notifier.emailClient = &EmailClient{
+		Host:            options.EmailHost,
+		Port:            options.EmailPort,
+		Username:        options.EmailUsername,
+		Password:        options.EmailPassword,
+		From:            options.EmailFrom,
+		To:              options.EmailTo,
+		TLS:             EmailTLSMode(options.EmailTLS),
+		SubjectTemplate: options.EmailSubjectTemplate,
+		HTML:            options.EmailHTML,
+		HTMLTemplate:    options.EmailHTMLTemplate,
+		TimeOut:         DefaultEmailTimeout,
+		capture:         notifier.rawCapture(ProviderEmail, "SMTP"),
+	}
+	notifier.pagerDutyClient = &PagerDutyClient{
+		client:     notifier.newProviderClient(ProviderPagerDuty),
+		RoutingKey: options.PagerDutyRoutingKey,
+		Source:     options.PagerDutySource,
+		DedupKey:   options.PagerDutyDedupKey,
+		TimeOut:    DefaultPagerDutyTimeout,
+	}
+	notifier.opsgenieClient = &OpsgenieClient{
+		client:     notifier.newProviderClient(ProviderOpsgenie),
+		URL:        options.OpsgenieURL,
+		APIKey:     options.OpsgenieAPIKey,
+		Tags:       options.OpsgenieTags,
+		Responders: options.OpsgenieResponders,
+		TimeOut:    DefaultOpsgenieTimeout,
+	}
+	notifier.zulipClient = &ZulipClient{
+		client:   notifier.newProviderClient(ProviderZulip),
+		URL:      options.ZulipURL,
+		BotEmail: options.ZulipBotEmail,
+		APIKey:   options.ZulipAPIKey,
+		Stream:   options.ZulipStream,
+		Topic:    options.ZulipTopic,
+		To:       options.ZulipTo,
+		TimeOut:  DefaultZulipTimeout,
+	}
+	notifier.xmppClient = &XMPPClient{
+		Server:    options.XMPPServer,
+		JID:       options.XMPPJID,
+		Password:  options.XMPPPassword,
+		To:        options.XMPPTo,
+		Room:      options.XMPPRoom,
+		Nick:      options.XMPPNick,
+		DirectTLS: options.XMPPDirectTLS,
+		TimeOut:   DefaultXMPPTimeout,
+		capture:   notifier.rawCapture(ProviderXMPP, "MESSAGE"),
+	}
+	notifier.ircClient = &IRCClient{
+		Server:       options.IRCServer,
+		TLS:          options.IRCTLS,
+		Nick:         options.IRCNick,
+		Password:     options.IRCPassword,
+		SASLUser:     options.IRCSASLUser,
+		SASLPassword: options.IRCSASLPassword,
+		Channel:      options.IRCChannel,
+		ChannelKey:   options.IRCChannelKey,
+		TimeOut:      DefaultIRCTimeout,
+		capture:      notifier.rawCapture(ProviderIRC, "PRIVMSG"),
+	}
+	notifier.dingTalkClient = &DingTalkClient{
+		client:     notifier.newProviderClient(ProviderDingTalk),
+		WebHookURL: options.DingTalkWebHookURL,
+		Secret:     options.DingTalkSecret,
+		AtMobiles:  options.DingTalkAtMobiles,
+		AtUserIDs:  options.DingTalkAtUserIDs,
+		AtAll:      options.DingTalkAtAll,
+		Markdown:   options.DingTalkMarkdown,
+		TimeOut:    DefaultDingTalkTimeout,
+	}
+	notifier.weComClient = &WeComClient{
+		client:              notifier.newProviderClient(ProviderWeCom),
+		WebHookURL:          options.WeComWebHookURL,
+		MentionedList:       options.WeComMentionedList,
+		MentionedMobileList: options.WeComMentionedMobileList,
+		Markdown:            options.WeComMarkdown,
+		TimeOut:             DefaultWeComTimeout,
+	}
+	notifier.larkClient = &LarkClient{
+		client:     notifier.newProviderClient(ProviderLark),
+		WebHookURL: options.LarkWebHookURL,
+		Secret:     options.LarkSecret,
+		Title:      options.LarkTitle,
+		TimeOut:    DefaultLarkTimeout,
+	}
+	notifier.barkClient = &BarkClient{
+		client:    notifier.newProviderClient(ProviderBark),
+		URL:       options.BarkURL,
+		DeviceKey: options.BarkDeviceKey,
+		Title:     options.BarkTitle,
+		Sound:     options.BarkSound,
+		Group:     options.BarkGroup,
+		Click:     options.BarkClick,
+		TimeOut:   DefaultBarkTimeout,
+	}
+	notifier.webexClient = &WebexClient{
+		client:      notifier.newProviderClient(ProviderWebex),
+		Token:       options.WebexToken,
+		RoomID:      options.WebexRoomID,
+		PersonEmail: options.WebexPersonEmail,
+		Markdown:    options.WebexMarkdown,
+		TimeOut:     DefaultWebexTimeout,
+	}
+	notifier.jiraClient = &JiraClient{
+		client:     notifier.newProviderClient(ProviderJira),
+		URL:        options.JiraURL,
+		Username:   options.JiraUsername,
+		Token:      options.JiraToken,
+		ProjectKey: options.JiraProjectKey,
+		IssueType:  options.JiraIssueType,
+		Labels:     options.JiraLabels,
+		Correlate:  options.JiraCorrelate,
+		TimeOut:    DefaultJiraTimeout,
+	}
+	notifier.gitHubClient = &GitHubClient{
+		client:         notifier.newProviderClient(ProviderGitHub),
+		URL:            options.GitHubURL,
+		Token:          options.GitHubToken,
+		Repository:     options.GitHubRepository,
+		SeverityLabels: options.GitHubSeverityLabels,
+		Correlate:      options.GitHubCorrelate,
+		TimeOut:        DefaultGitHubTimeout,
+	}
+	notifier.gitLabClient = &GitLabClient{
+		client:         notifier.newProviderClient(ProviderGitLab),
+		URL:            options.GitLabURL,
+		Token:          options.GitLabToken,
+		Project:        options.GitLabProject,
+		SeverityLabels: options.GitLabSeverityLabels,
+		Correlate:      options.GitLabCorrelate,
+		TimeOut:        DefaultGitLabTimeout,
+	}
+	notifier.kafkaClient = &KafkaClient{
+		Brokers:      options.KafkaBrokers,
+		Topic:        options.KafkaTopic,
+		KeySource:    options.KafkaKeySource,
+		Tag:          options.KafkaTag,
+		TLS:          options.KafkaTLS,
+		SASLUsername: options.KafkaSASLUsername,
+		SASLPassword: options.KafkaSASLPassword,
+		TimeOut:      DefaultKafkaTimeout,
+		capture:      notifier.rawCapture(ProviderKafka, "PRODUCE"),
+	}
+	notifier.natsClient = &NATSClient{
+		URL:             options.NATSURL,
+		Subject:         options.NATSSubject,
+		Token:           options.NATSToken,
+		CredentialsFile: options.NATSCredentialsFile,
+		TLS:             options.NATSTLS,
+		JetStream:       options.NATSJetStream,
+		TimeOut:         DefaultNATSTimeout,
+		capture:         notifier.rawCapture(ProviderNATS, "PUB"),
+	}
+	notifier.datadogClient = &DatadogClient{
+		client:         notifier.newProviderClient(ProviderDatadog),
+		Site:           options.DatadogSite,
+		APIKey:         options.DatadogAPIKey,
+		Tags:           options.DatadogTags,
+		AggregationKey: options.DatadogAggregationKey,
+		TimeOut:        DefaultDatadogTimeout,
+	}
+	notifier.sentryClient = &SentryClient{
+		client:      notifier.newProviderClient(ProviderSentry),
+		DSN:         options.SentryDSN,
+		Environment: options.SentryEnvironment,
+		Tags:        options.SentryTags,
+		Fingerprint: options.SentryFingerprint,
+		TimeOut:     DefaultSentryTimeout,
+	}
+	notifier.customWebhookClient = &CustomWebhookClient{
+		client:       notifier.newProviderClient(ProviderCustomWebhook),
+		URL:          options.CustomWebhookURL,
+		Method:       options.CustomWebhookMethod,
+		Headers:      options.CustomWebhookHeaders,
+		BodyTemplate: options.CustomWebhookBodyTemplate,
+		TimeOut:      DefaultCustomWebhookTimeout,
+	}
+	notifier.sesClient = &SESClient{
+		client:           notifier.newProviderClient(ProviderSES),
+		Region:           options.SESRegion,
+		Credentials:      AWSCredentials{AccessKeyID: options.SESAccessKeyID, SecretAccessKey: options.SESSecretAccessKey},
+		From:             options.SESFrom,
+		To:               options.SESTo,
+		SubjectTemplate:  options.SESSubjectTemplate,
+		HTMLTemplate:     options.SESHTMLTemplate,
+		ConfigurationSet: options.SESConfigurationSet,
+		TimeOut:          DefaultSESTimeout,
+	}
+	notifier.nextcloudTalkClient = &NextcloudTalkClient{
+		client:      notifier.newProviderClient(ProviderNextcloudTalk),
+		URL:         options.NextcloudTalkURL,
+		Username:    options.NextcloudTalkUsername,
+		AppPassword: options.NextcloudTalkAppPassword,
+		RoomToken:   options.NextcloudTalkRoomToken,
+		TimeOut:     DefaultNextcloudTalkTimeout,
+	}
+	notifier.serviceURLClient = &ServiceURLClient{
+		client:  notifier.newProviderClient(ProviderServiceURL),
+		URLs:    options.ServiceURLs,
+		TimeOut: DefaultServiceURLTimeout,
+	}
+	if interval := options.BatchFlushInterval; interval >= 0 && options.batching() {
+		if interval == 0 {
+			interval = DefaultBatchFlushInterval
+		}
+		notifier.flusher = notifier.startBatchFlusher(interval)
+	}
+	if len(restored) > 0 {
+		notifier.restore(restored)
+	}
+	return notifier, nil
+}
+
+// newProviderClient returns an http client accounting retries to the provider
+func (n *Notify) newProviderClient(name string) *retryablehttp.Client {
+	var client *retryablehttp.Client
+	if n.options != nil {
+		client = newRetryClient(n.options.retryPolicy(name))
+	} else {
+		client = retryablehttp.NewClient(retryablehttp.DefaultOptionsSingle)
+	}
+	client.ErrorHandler = exhaustedRetries
+	if n.options != nil {
+		if proxy := n.options.proxyURL(name); proxy != "" {
+			setProxy(client, proxy)
+		}
+	}
+	if n.options != nil && n.options.Capture {
+		client.HTTPClient.Transport = &captureTransport{provider: name, forward: n.options.CaptureForwardURL, store: n.captures, next: client.HTTPClient.Transport}
+	}
+	client.HTTPClient.Transport = &attemptTimeoutTransport{next: client.HTTPClient.Transport}
+	client.RequestLogHook = func(_ *http.Request, attempt int) {
+		if attempt > 0 {
+			n.stats.retried(name)
+			n.events.publish(&Event{Type: EventRetried, Provider: name, Attempt: attempt})
+		}
+	}
+	return client
+}
+
+// enabledProviders returns the webhooks enabled in the options
+func (n *Notify) enabledProviders() []provider {
+	if n.options == nil {
+		return nil
+	}
+	var providers []provider
+	if n.options.Slack {
+		p := provider{name: ProviderSlack, send: func(message string) error {
+			return n.slackClient.SendInfo(message)
+		}, sendResult: func(ctx context.Context, message string) (*DeliveryResult, error) {
+			return n.slackClient.SendInfoResult(ctx, message)
+		}}
+		if n.options.ConfirmDelivery && n.slackClient.mode() == SlackModeToken {
+			p.sendConfirmed = n.slackClient.SendConfirmedCtx
+		}
+		providers = append(providers, p)
+	}
+	if n.options.Discord {
+		providers = append(providers, provider{name: ProviderDiscord, send: n.discordClient.SendInfo, sendResult: n.discordClient.SendInfoResult})
+	}
+	if n.options.Telegram {
+		p := provider{name: ProviderTelegram, send: n.telegramClient.SendInfo, sendResult: n.telegramClient.SendInfoResult}
+		if n.options.ConfirmDelivery {
+			p.sendConfirmed = n.telegramClient.SendConfirmedCtx
+		}
+		providers = append(providers, p)
+	}
+	if n.options.S3 {
+		providers = append(providers, provider{name: ProviderS3, send: n.s3Client.SendInfo, sendCtx: n.s3Client.SendInfoCtx, flush: n.s3Client.Flush})
+	}
+	if n.options.Elasticsearch {
+		providers = append(providers, provider{name: ProviderElasticsearch, send: n.esClient.SendInfo, sendCtx: n.esClient.SendInfoCtx, flush: n.esClient.Flush})
+	}
+	if n.options.Splunk {
+		providers = append(providers, provider{name: ProviderSplunk, send: n.splunkClient.SendInfo, sendCtx: n.splunkClient.SendInfoCtx, flush: n.splunkClient.Flush})
+	}
+	if n.options.Loki {
+		providers = append(providers, provider{name: ProviderLoki, send: n.lokiClient.SendInfo, sendCtx: n.lokiClient.SendInfoCtx})
+	}
+	if n.options.ClickHouse {
+		providers = append(providers, provider{name: ProviderClickHouse, send: n.clickHouseClient.SendInfo, sendCtx: n.clickHouseClient.SendInfoCtx, flush: n.clickHouseClient.Flush})
+	}
+	if n.options.Grafana {
+		providers = append(providers, provider{name: ProviderGrafana, send: n.grafanaClient.SendInfo, sendCtx: n.grafanaClient.SendInfoCtx})
+	}
+	if n.options.InfluxDB {
+		providers = append(providers, provider{name: ProviderInfluxDB, send: n.influxDBClient.SendInfo, sendCtx: n.influxDBClient.SendInfoCtx})
+	}
+	if n.options.Chime {
+		providers = append(providers, provider{name: ProviderChime, send: n.chimeClient.SendInfo, sendCtx: n.chimeClient.SendInfoCtx})
+	}
+	if n.options.Bitrix24 {
+		providers = append(providers, provider{name: ProviderBitrix24, send: n.bitrix24Client.SendInfo, sendCtx: n.bitrix24Client.SendInfoCtx})
+	}
+	if n.options.Teams {
+		providers = append(providers, provider{name: ProviderTeams, send: n.teamsClient.SendInfo, sendResult: n.teamsClient.SendInfoResult})
+	}
+	if n.options.CloudEvents {
+		providers = append(providers, provider{name: ProviderCloudEvents, send: n.cloudEventsClient.SendInfo, sendCtx: n.cloudEventsClient.SendInfoCtx})
+	}
+	if n.options.Mattermost {
+		providers = append(providers, provider{name: ProviderMattermost, send: n.mattermostClient.SendInfo, sendCtx: n.mattermostClient.SendInfoCtx})
+	}
+	if n.options.GoogleChat {
+		providers = append(providers, provider{name: ProviderGoogleChat, send: n.googleChatClient.SendInfo, sendCtx: n.googleChatClient.SendInfoCtx})
+	}
+	if n.options.Pushover {
+		providers = append(providers, provider{name: ProviderPushover, send: n.pushoverClient.SendInfo, sendCtx: n.pushoverClient.SendInfoCtx})
+	}
+	if n.options.Gotify {
+		providers = append(providers, provider{name: ProviderGotify, send: n.gotifyClient.SendInfo, sendCtx: n.gotifyClient.SendInfoCtx})
+	}
+	if n.options.Ntfy {
+		providers = append(providers, provider{name: ProviderNtfy, send: n.ntfyClient.SendInfo, sendCtx: n.ntfyClient.SendInfoCtx})
+	}
+	if n.options.Matrix {
+		providers = append(providers, provider{name: ProviderMatrix, send: n.matrixClient.SendInfo, sendCtx: n.matrixClient.SendInfoCtx})
+	}
+	if n.options.Signal {
+		providers = append(providers, provider{name: ProviderSignal, send: n.signalClient.SendInfo, sendCtx: n.signalClient.SendInfoCtx})
+	}
+	if n.options.SNS {
+		providers = append(providers, provider{name: ProviderSNS, send: n.snsClient.SendInfo, sendCtx: n.snsClient.SendInfoCtx})
+	}
+	if n.options.Email {
+		providers = append(providers, provider{name: ProviderEmail, send: n.emailClient.SendInfo, sendCtx: n.emailClient.SendInfoCtx})
+	}
+	if n.options.PagerDuty {
+		providers = append(providers, provider{name: ProviderPagerDuty, send: n.pagerDutyClient.SendInfo, sendCtx: n.pagerDutyClient.SendInfoCtx})
+	}
+	if n.options.Opsgenie {
+		providers = append(providers, provider{name: ProviderOpsgenie, send: n.opsgenieClient.SendInfo, sendCtx: n.opsgenieClient.SendInfoCtx})
+	}
+	if n.options.Zulip {
+		providers = append(providers, provider{name: ProviderZulip, send: n.zulipClient.SendInfo, sendCtx: n.zulipClient.SendInfoCtx})
+	}
+	if n.options.XMPP {
+		providers = append(providers, provider{name: ProviderXMPP, send: n.xmppClient.SendInfo, sendCtx: n.xmppClient.SendInfoCtx})
+	}
+	if n.options.IRC {
+		providers = append(providers, provider{name: ProviderIRC, send: n.ircClient.SendInfo, sendCtx: n.ircClient.SendInfoCtx})
+	}
+	if n.options.DingTalk {
+		providers = append(providers, provider{name: ProviderDingTalk, send: n.dingTalkClient.SendInfo, sendCtx: n.dingTalkClient.SendInfoCtx})
+	}
+	if n.options.WeCom {
+		providers = append(providers, provider{name: ProviderWeCom, send: n.weComClient.SendInfo, sendCtx: n.weComClient.SendInfoCtx})
+	}
+	if n.options.Lark {
+		providers = append(providers, provider{name: ProviderLark, send: n.larkClient.SendInfo, sendCtx: n.larkClient.SendInfoCtx})
+	}
+	if n.options.Bark {
+		providers = append(providers, provider{name: ProviderBark, send: n.barkClient.SendInfo, sendCtx: n.barkClient.SendInfoCtx})
+	}
+	if n.options.Webex {
+		providers = append(providers, provider{name: ProviderWebex, send: n.webexClient.SendInfo, sendCtx: n.webexClient.SendInfoCtx})
+	}
+	if n.options.Jira {
+		providers = append(providers, provider{name: ProviderJira, send: n.jiraClient.SendInfo, sendCtx: n.jiraClient.SendInfoCtx})
+	}
+	if n.options.GitHub {
+		providers = append(providers, provider{name: ProviderGitHub, send: n.gitHubClient.SendInfo, sendCtx: n.gitHubClient.SendInfoCtx})
+	}
+	if n.options.GitLab {
+		providers = append(providers, provider{name: ProviderGitLab, send: n.gitLabClient.SendInfo, sendCtx: n.gitLabClient.SendInfoCtx})
+	}
+	if n.options.Kafka {
+		providers = append(providers, provider{name: ProviderKafka, send: n.kafkaClient.SendInfo, sendCtx: n.kafkaClient.SendInfoCtx})
+	}
+	if n.options.NATS {
+		providers = append(providers, provider{name: ProviderNATS, send: n.natsClient.SendInfo, sendCtx: n.natsClient.SendInfoCtx})
+	}
+	if n.options.Datadog {
+		providers = append(providers, provider{name: ProviderDatadog, send: n.datadogClient.SendInfo, sendCtx: n.datadogClient.SendInfoCtx})
+	}
+	if n.options.Sentry {
+		providers = append(providers, provider{name: ProviderSentry, send: n.sentryClient.SendInfo, sendCtx: n.sentryClient.SendInfoCtx})
+	}
+	if n.options.CustomWebhook {
+		providers = append(providers, provider{name: ProviderCustomWebhook, send: n.customWebhookClient.SendInfo, sendCtx: n.customWebhookClient.SendInfoCtx})
+	}
+	if n.options.SES {
+		providers = append(providers, provider{name: ProviderSES, send: n.sesClient.SendInfo, sendCtx: n.sesClient.SendInfoCtx})
+	}
+	if n.options.NextcloudTalk {
+		providers = append(providers, provider{name: ProviderNextcloudTalk, send: n.nextcloudTalkClient.SendInfo, sendCtx: n.nextcloudTalkClient.SendInfoCtx})
+	}
+	if len(n.options.ServiceURLs) > 0 {
+		providers = append(providers, provider{name: ProviderServiceURL, send: n.serviceURLClient.SendInfo, sendCtx: n.serviceURLClient.SendInfoCtx})
+	}
+	for _, name := range n.options.Notifiers {
+		notifier, ok := n.notifiers[name]
+		if !ok {
+			continue
+		}
+		providers = append(providers, provider{name: name, send: func(message string) error {
+			return notifier.Send(context.Background(), &Message{Text: message})
+		}, sendCtx: func(ctx context.Context, message string) error {
+			return notifier.Send(ctx, &Message{Text: message})
+		}})
+	}
+	return providers
 }
 
 // SendNotification to registered webhooks
 func (n *Notify) SendNotification(message string) error {
+	_, err := n.deliver(context.Background(), message, false)
+	return err
+}
+
+// SendNotificationCtx to registered webhooks, the context cancels in-flight
+// requests, bounds the exchanges of the providers not using http and skips
+// the remaining providers
+func (n *Notify) SendNotificationCtx(ctx context.Context, message string) error {
+	_, err := n.deliver(ctx, message, false)
+	return err
+}
+
+// SendNotificationResults to registered webhooks returning what each provider
+// delivered, eg. the message ids needed to edit or reply to the messages later.
+// Only slack, discord, telegram and teams describe their deliveries, the
+// results of the other providers hold the provider name and timestamp.
+func (n *Notify) SendNotificationResults(ctx context.Context, message string) ([]*DeliveryResult, error) {
+	return n.deliver(ctx, message, false)
+}
+
+// deliver sends the message to the enabled webhooks, failures of
+// async deliveries are final and reported as dead-lettered. A failed group
+// doesn't prevent the delivery to the other ones, a *MultiError describes
+// the outcome when several groups received the message and one failed.
+func (n *Notify) deliver(ctx context.Context, message string, async bool) ([]*DeliveryResult, error) {
 	// strip unsupported color control chars
 	message = stripansi.Strip(message)
-	if n.options.Slack {
-		err := n.slackClient.SendInfo(message)
+	groups := n.deliveryGroups()
+	var results []*DeliveryResult
+	multiErr := &MultiError{}
+	for _, group := range groups {
+		result, err := n.deliverGroup(ctx, group, message, async)
 		if err != nil {
-			return err
+			multiErr.Failed = append(multiErr.Failed, err)
+			continue
 		}
+		results = append(results, result)
+		multiErr.Succeeded = append(multiErr.Succeeded, result.Provider)
 	}
 
-	if n.options.Discord {
-		err := n.discordClient.SendInfo(message)
-		if err != nil {
-			return err
+	switch {
+	case len(multiErr.Failed) == 0:
+		return results, nil
+	case len(groups) == 1:
+		return results, multiErr.Failed[0]
+	default:
+		return results, multiErr
+	}
+}
+
+// deliverGroup sends the message to the healthiest provider of the group,
+// falling back to the next ones on failure
+func (n *Notify) deliverGroup(ctx context.Context, group []provider, message string, async bool) (*DeliveryResult, error) {
+	var err error
+	var last provider
+	for _, p := range n.health.order(group) {
+		last = p
+		var result *DeliveryResult
+		if result, err = n.deliverProvider(ctx, p, message, async); err == nil {
+			return result, nil
 		}
 	}
+	if async {
+		n.events.publish(&Event{Type: EventDeadLettered, Provider: last.name, Message: message, Error: err})
+	}
+	return nil, err
+}
 
-	if n.options.Telegram {
-		err := n.telegramClient.SendInfo(message)
-		if err != nil {
-			return err
+// deliverProvider sends the message unless the circuit of the provider is open,
+// the fallback provider of the circuit breaker is used instead while open,
+// itself subject to its circuit. Each provider is tried once so that
+// fallbacks pointing at each other don't loop.
+func (n *Notify) deliverProvider(ctx context.Context, p provider, message string, async bool) (*DeliveryResult, error) {
+	tried := make(map[string]bool)
+	for {
+		tried[p.name] = true
+		breaker := n.options.circuitBreaker(p.name)
+		if n.circuits.allow(p.name, breaker) {
+			result, err := n.deliverAttempt(ctx, p, message, async)
+			if n.circuits.record(p.name, breaker, err == nil) {
+				n.events.publish(&Event{Type: EventCircuitOpened, Provider: p.name, Error: err})
+			}
+			return result, err
+		}
+		err := withProvider(p.name, ErrCircuitOpen)
+		n.events.publish(&Event{Type: EventFailed, Provider: p.name, Message: message, Error: err})
+		fallback, ok := n.enabledProvider(breaker.Fallback)
+		if !ok || tried[fallback.name] {
+			return nil, err
+		}
+		p = fallback
+	}
+}
+
+// enabledProvider returns the enabled provider by name
+func (n *Notify) enabledProvider(name string) (provider, bool) {
+	if name == "" {
+		return provider{}, false
+	}
+	for _, p := range n.enabledProviders() {
+		if p.name == name {
+			return p, true
 		}
 	}
+	return provider{}, false
+}
 
-	return nil
+func (n *Notify) deliverAttempt(ctx context.Context, p provider, message string, async bool) (*DeliveryResult, error) {
+	var err error
+	var result *DeliveryResult
+	send := func() {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+		if limiter, ok := n.limiters[p.name]; ok {
+			if err = limiter.wait(ctx); err != nil {
+				return
+			}
+		}
+		var text string
+		if text, err = n.templates.render(p.name, SeverityInfo, message); err != nil {
+			return
+		}
+		if p.sendConfirmed == nil {
+			switch {
+			case p.sendResult != nil:
+				result, err = p.sendResult(ctx, text)
+			case p.sendCtx != nil:
+				err = p.sendCtx(ctx, text)
+			default:
+				err = p.send(text)
+			}
+			if err == nil && result == nil {
+				// the provider doesn't report the message id nor the status code
+				result = newDeliveryResult(p.name, 0)
+			}
+			return
+		}
+		if result, err = p.sendConfirmed(ctx, text); err == nil && !result.Confirmed {
+			err = ErrNotConfirmed
+		}
+	}
+	if async {
+		pprof.Do(ctx, pprof.Labels("provider", p.name), func(context.Context) {
+			send()
+		})
+	} else {
+		send()
+	}
+	if err != nil {
+		err = withProvider(p.name, err)
+	}
+	n.stats.record(p.name, err)
+	n.health.record(p.name, err == nil)
+	if err != nil {
+		n.events.publish(&Event{Type: EventFailed, Provider: p.name, Message: message, Error: err})
+		return nil, err
+	}
+	n.events.publish(&Event{Type: EventSent, Provider: p.name, Message: message, Result: result})
+	return result, nil
 }
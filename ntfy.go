@@ -0,0 +1,129 @@
+package notify
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// DefaultNtfyTimeout to conclude operations
+const DefaultNtfyTimeout = 5 * time.Second
+
+// DefaultNtfyURL is the public ntfy server
+const DefaultNtfyURL = "https://ntfy.sh"
+
+// Ntfy priorities
+const (
+	NtfyPriorityMin     = 1
+	NtfyPriorityLow     = 2
+	NtfyPriorityDefault = 3
+	NtfyPriorityHigh    = 4
+	NtfyPriorityMax     = 5
+)
+
+// NtfyClient publishing to a ntfy topic
+type NtfyClient struct {
+	client *retryablehttp.Client
+	// URL of the server, DefaultNtfyURL if empty
+	URL   string
+	Topic string
+	// Token authenticates to protected topics
+	Token    string
+	Title    string
+	Priority int
+	Tags     []string
+	// Click is opened when the notification is tapped
+	Click   string
+	TimeOut time.Duration
+}
+
+// NtfyMessage structure
+type NtfyMessage struct {
+	Message  string
+	Title    string
+	Priority int
+	// Tags are shown as emojis when matching a short code
+	Tags  []string
+	Click string
+}
+
+// SendInfo to ntfy
+func (nc *NtfyClient) SendInfo(message string) error {
+	return nc.SendInfoCtx(context.Background(), message)
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (nc *NtfyClient) SendInfoCtx(ctx context.Context, message string) error {
+	return nc.SendNtfyNotificationCtx(ctx, &NtfyMessage{Message: message, Priority: nc.Priority, Tags: nc.Tags})
+}
+
+// SendWarning to ntfy
+func (nc *NtfyClient) SendWarning(message string) error {
+	return nc.SendNtfyNotificationCtx(context.Background(), &NtfyMessage{Message: message, Priority: NtfyPriorityHigh, Tags: append([]string{"warning"}, nc.Tags...)})
+}
+
+// SendError to ntfy
+func (nc *NtfyClient) SendError(message string) error {
+	return nc.SendNtfyNotificationCtx(context.Background(), &NtfyMessage{Message: message, Priority: NtfyPriorityMax, Tags: append([]string{"rotating_light"}, nc.Tags...)})
+}
+
+// SendNtfyNotification publishes the message, the client title and click are used when unset
+func (nc *NtfyClient) SendNtfyNotification(ntfyMessage *NtfyMessage) error {
+	return nc.SendNtfyNotificationCtx(context.Background(), ntfyMessage)
+}
+
+// SendNtfyNotificationCtx is SendNtfyNotification canceled with the context
+func (nc *NtfyClient) SendNtfyNotificationCtx(ctx context.Context, ntfyMessage *NtfyMessage) error {
+	server := nc.URL
+	if server == "" {
+		server = DefaultNtfyURL
+	}
+	req, err := retryablehttp.NewRequest(http.MethodPost, strings.TrimSuffix(server, "/")+"/"+nc.Topic, []byte(ntfyMessage.Message))
+	if err != nil {
+		return err
+	}
+	title := ntfyMessage.Title
+	if title == "" {
+		title = nc.Title
+	}
+	if title != "" {
+		req.Header.Set("Title", title)
+	}
+	if ntfyMessage.Priority != 0 {
+		req.Header.Set("Priority", strconv.Itoa(ntfyMessage.Priority))
+	}
+	if len(ntfyMessage.Tags) > 0 {
+		req.Header.Set("Tags", strings.Join(ntfyMessage.Tags, ","))
+	}
+	click := ntfyMessage.Click
+	if click == "" {
+		click = nc.Click
+	}
+	if click != "" {
+		req.Header.Set("Click", click)
+	}
+	if nc.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+nc.Token)
+	}
+
+	resp, err := doTimeout(nc.client, req.WithContext(ctx), nc.TimeOut)
+	if err != nil {
+		return err
+	}
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck // silent fail
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newResponseError(resp, buf)
+	}
+	return nil
+}
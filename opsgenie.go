@@ -0,0 +1,138 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// DefaultOpsgenieTimeout to conclude operations
+const DefaultOpsgenieTimeout = 5 * time.Second
+
+// DefaultOpsgenieURL is the api of the us instance, eu accounts use https://api.eu.opsgenie.com
+const DefaultOpsgenieURL = "https://api.opsgenie.com"
+
+// opsgenieMaxMessage is the longest alert message, the full text goes in the description
+const opsgenieMaxMessage = 130
+
+// Opsgenie priorities
+const (
+	OpsgenieP1 = "P1"
+	OpsgenieP2 = "P2"
+	OpsgenieP3 = "P3"
+	OpsgenieP4 = "P4"
+	OpsgenieP5 = "P5"
+)
+
+// OpsgenieClient creates alerts with the alerts api
+type OpsgenieClient struct {
+	client *retryablehttp.Client
+	// URL of the api, DefaultOpsgenieURL if empty
+	URL    string
+	APIKey string
+	Tags   []string
+	// Responders are notified of the alerts
+	Responders []OpsgenieResponder
+	TimeOut    time.Duration
+}
+
+// OpsgenieResponder is a team, user, escalation or schedule referenced by name, username or id
+type OpsgenieResponder struct {
+	Type     string `json:"type"`
+	ID       string `json:"id,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Username string `json:"username,omitempty"`
+}
+
+// OpsgenieAlert json structure
+type OpsgenieAlert struct {
+	Message     string              `json:"message"`
+	Alias       string              `json:"alias,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Responders  []OpsgenieResponder `json:"responders,omitempty"`
+	Tags        []string            `json:"tags,omitempty"`
+	Source      string              `json:"source,omitempty"`
+	Priority    string              `json:"priority,omitempty"`
+}
+
+// SendInfo to opsgenie as P5 alert
+func (oc *OpsgenieClient) SendInfo(message string) error {
+	return oc.SendInfoCtx(context.Background(), message)
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (oc *OpsgenieClient) SendInfoCtx(ctx context.Context, message string) error {
+	return oc.send(ctx, message, OpsgenieP5)
+}
+
+// SendWarning to opsgenie as P3 alert
+func (oc *OpsgenieClient) SendWarning(message string) error {
+	return oc.send(context.Background(), message, OpsgenieP3)
+}
+
+// SendError to opsgenie as P1 alert
+func (oc *OpsgenieClient) SendError(message string) error {
+	return oc.send(context.Background(), message, OpsgenieP1)
+}
+
+func (oc *OpsgenieClient) send(ctx context.Context, message, priority string) error {
+	summary := message
+	if i := strings.IndexByte(summary, '\n'); i >= 0 {
+		summary = summary[:i]
+	}
+	if runes := []rune(summary); len(runes) > opsgenieMaxMessage {
+		summary = string(runes[:opsgenieMaxMessage])
+	}
+	return oc.SendOpsgenieAlertCtx(ctx, &OpsgenieAlert{
+		Message:     summary,
+		Description: message,
+		Responders:  oc.Responders,
+		Tags:        oc.Tags,
+		Source:      "notify",
+		Priority:    priority,
+	})
+}
+
+// SendOpsgenieAlert with json structure
+func (oc *OpsgenieClient) SendOpsgenieAlert(alert *OpsgenieAlert) error {
+	return oc.SendOpsgenieAlertCtx(context.Background(), alert)
+}
+
+// SendOpsgenieAlertCtx is SendOpsgenieAlert canceled with the context
+func (oc *OpsgenieClient) SendOpsgenieAlertCtx(ctx context.Context, alert *OpsgenieAlert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	server := oc.URL
+	if server == "" {
+		server = DefaultOpsgenieURL
+	}
+	req, err := retryablehttp.NewRequest(http.MethodPost, strings.TrimSuffix(server, "/")+"/v2/alerts", body)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Authorization", "GenieKey "+oc.APIKey)
+
+	resp, err := doTimeout(oc.client, req.WithContext(ctx), oc.TimeOut)
+	if err != nil {
+		return err
+	}
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck // silent fail
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return newResponseError(resp, buf)
+	}
+	return nil
+}
@@ -1,22 +1,445 @@
 package notify
 
+import "time"
+
 // Options of internal webhooks
+//
 //nolint:maligned // used once
 type Options struct {
 	// Slack
 	SlackWebHookURL string
-	SlackUsername   string
-	SlackChannel    string
-	Slack           bool
+	// SlackWebHookURLs are several webhooks used according to SlackWebHookStrategy,
+	// failover or roundrobin
+	SlackWebHookURLs     []string
+	SlackWebHookStrategy string
+	SlackUsername        string
+	SlackChannel         string
+	SlackToken           string
+	SlackIconURL         string
+	SlackMode            string
+	SlackThreadTS        string
+	// SlackDisableUnfurl prevents url previews in the messages
+	SlackDisableUnfurl bool
+	Slack              bool
 
 	// Discord
 	DiscordWebHookURL       string
+	DiscordWebHookURLs      []string
+	DiscordWebHookStrategy  string
 	DiscordWebHookUsername  string
 	DiscordWebHookAvatarURL string
+	DiscordCriticalRoles    []string
 	Discord                 bool
 
 	// Telegram
 	TelegramAPIKey string
 	TelegramChatID string
-	Telegram       bool
+	// TelegramParseMode is MarkdownV2, HTML or empty for plain text
+	TelegramParseMode string
+	Telegram          bool
+
+	// S3
+	S3Endpoint        string
+	S3Region          string
+	S3Bucket          string
+	S3Prefix          string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+	S3PathStyle       bool
+	S3BatchSize       int
+	S3                bool
+
+	// Elasticsearch
+	ElasticsearchURL       string
+	ElasticsearchIndex     string
+	ElasticsearchUsername  string
+	ElasticsearchPassword  string
+	ElasticsearchAPIKey    string
+	ElasticsearchSource    string
+	ElasticsearchTemplate  bool
+	ElasticsearchBatchSize int
+	Elasticsearch          bool
+
+	// Splunk
+	SplunkURL        string
+	SplunkToken      string
+	SplunkSourceType string
+	SplunkIndex      string
+	SplunkSource     string
+	SplunkHost       string
+	SplunkBatchSize  int
+	Splunk           bool
+
+	// Loki
+	LokiURL      string
+	LokiLabels   map[string]string
+	LokiUsername string
+	LokiPassword string
+	LokiTenantID string
+	Loki         bool
+
+	// ClickHouse
+	ClickHouseURL         string
+	ClickHouseDatabase    string
+	ClickHouseTable       string
+	ClickHouseUsername    string
+	ClickHousePassword    string
+	ClickHouseCreateTable bool
+	ClickHouseBatchSize   int
+	ClickHouse            bool
+
+	// Grafana
+	GrafanaURL          string
+	GrafanaAPIKey       string
+	GrafanaDashboardUID string
+	GrafanaPanelID      int
+	GrafanaTags         []string
+	Grafana             bool
+
+	// InfluxDB
+	InfluxDBURL         string
+	InfluxDBToken       string
+	InfluxDBOrg         string
+	InfluxDBBucket      string
+	InfluxDBMeasurement string
+	InfluxDBTags        map[string]string
+	InfluxDB            bool
+
+	// Chime
+	ChimeWebHookURL string
+	ChimeMarkdown   bool
+	ChimeMention    string
+	Chime           bool
+
+	// Bitrix24
+	Bitrix24WebHookURL string
+	Bitrix24DialogID   string
+	Bitrix24BotID      string
+	Bitrix24ClientID   string
+	Bitrix24           bool
+
+	// Teams
+	TeamsWebHookURL string
+	TeamsMentions   []TeamsMention
+	// TeamsCardFormat is adaptive (default) or messagecard
+	TeamsCardFormat string
+	Teams           bool
+
+	// SeverityScheme overrides the colors, emojis and labels of the severities
+	// on the slack, discord, teams and mattermost providers
+	SeverityScheme SeverityScheme
+
+	// ConfirmDelivery fetches messages back after sending on providers supporting
+	// it (slack bot token, telegram), unconfirmed deliveries are failures
+	ConfirmDelivery bool
+
+	// FailoverGroups lists provider names delivering each message once,
+	// the healthiest member in order of preference is used
+	FailoverGroups [][]string
+
+	// CloudEvents
+	CloudEventsURL    string
+	CloudEventsSource string
+	CloudEventsType   string
+	CloudEventsMode   string
+	CloudEvents       bool
+
+	// Mattermost
+	MattermostWebHookURL string
+	MattermostChannel    string
+	MattermostUsername   string
+	MattermostIconURL    string
+	MattermostIconEmoji  string
+	Mattermost           bool
+
+	// Google Chat
+	GoogleChatWebHookURL string
+	GoogleChatThreadKey  string
+	GoogleChat           bool
+
+	// Pushover
+	PushoverToken    string
+	PushoverUserKey  string
+	PushoverDevice   string
+	PushoverPriority int
+	PushoverSound    string
+	Pushover         bool
+
+	// Gotify
+	GotifyURL      string
+	GotifyToken    string
+	GotifyTitle    string
+	GotifyMarkdown bool
+	Gotify         bool
+
+	// ntfy
+	NtfyURL      string
+	NtfyTopic    string
+	NtfyToken    string
+	NtfyTitle    string
+	NtfyPriority int
+	NtfyTags     []string
+	NtfyClick    string
+	Ntfy         bool
+
+	// Matrix
+	MatrixHomeserverURL string
+	MatrixAccessToken   string
+	MatrixRoomID        string
+	MatrixMsgType       string
+	MatrixHTML          bool
+	Matrix              bool
+
+	// Signal
+	SignalURL        string
+	SignalNumber     string
+	SignalRecipients []string
+	Signal           bool
+
+	// SNS
+	SNSTopicARN        string
+	SNSRegion          string
+	SNSAccessKeyID     string
+	SNSSecretAccessKey string
+	SNSSubject         string
+	SNS                bool
+
+	// Email
+	EmailHost            string
+	EmailPort            int
+	EmailUsername        string
+	EmailPassword        string
+	EmailFrom            string
+	EmailTo              []string
+	EmailTLS             string
+	EmailSubjectTemplate string
+	EmailHTML            bool
+	EmailHTMLTemplate    string
+	Email                bool
+
+	// PagerDuty
+	PagerDutyRoutingKey string
+	PagerDutySource     string
+	PagerDutyDedupKey   string
+	PagerDuty           bool
+
+	// Opsgenie
+	OpsgenieURL        string
+	OpsgenieAPIKey     string
+	OpsgenieTags       []string
+	OpsgenieResponders []OpsgenieResponder
+	Opsgenie           bool
+
+	// Zulip
+	ZulipURL      string
+	ZulipBotEmail string
+	ZulipAPIKey   string
+	ZulipStream   string
+	ZulipTopic    string
+	ZulipTo       []string
+	Zulip         bool
+
+	// XMPP
+	XMPPServer    string
+	XMPPJID       string
+	XMPPPassword  string
+	XMPPTo        string
+	XMPPRoom      string
+	XMPPNick      string
+	XMPPDirectTLS bool
+	XMPP          bool
+
+	// IRC
+	IRCServer       string
+	IRCTLS          bool
+	IRCNick         string
+	IRCPassword     string
+	IRCSASLUser     string
+	IRCSASLPassword string
+	IRCChannel      string
+	IRCChannelKey   string
+	IRC             bool
+
+	// DingTalk
+	DingTalkWebHookURL string
+	DingTalkSecret     string
+	DingTalkAtMobiles  []string
+	DingTalkAtUserIDs  []string
+	DingTalkAtAll      bool
+	DingTalkMarkdown   bool
+	DingTalk           bool
+
+	// WeCom
+	WeComWebHookURL          string
+	WeComMentionedList       []string
+	WeComMentionedMobileList []string
+	WeComMarkdown            bool
+	WeCom                    bool
+
+	// Lark
+	LarkWebHookURL string
+	LarkSecret     string
+	LarkTitle      string
+	Lark           bool
+
+	// Bark
+	BarkURL       string
+	BarkDeviceKey string
+	BarkTitle     string
+	BarkSound     string
+	BarkGroup     string
+	BarkClick     string
+	Bark          bool
+
+	// Webex
+	WebexToken       string
+	WebexRoomID      string
+	WebexPersonEmail string
+	WebexMarkdown    bool
+	Webex            bool
+
+	// Jira
+	JiraURL        string
+	JiraUsername   string
+	JiraToken      string
+	JiraProjectKey string
+	JiraIssueType  string
+	JiraLabels     []string
+	JiraCorrelate  bool
+	Jira           bool
+
+	// GitHub
+	GitHubURL            string
+	GitHubToken          string
+	GitHubRepository     string
+	GitHubSeverityLabels map[string]string
+	GitHubCorrelate      bool
+	GitHub               bool
+
+	// GitLab
+	GitLabURL            string
+	GitLabToken          string
+	GitLabProject        string
+	GitLabSeverityLabels map[string]string
+	GitLabCorrelate      bool
+	GitLab               bool
+
+	// Kafka
+	KafkaBrokers []string
+	KafkaTopic   string
+	// KafkaKeySource is severity, tag or empty for records without key
+	KafkaKeySource    string
+	KafkaTag          string
+	KafkaTLS          bool
+	KafkaSASLUsername string
+	KafkaSASLPassword string
+	Kafka             bool
+
+	// NATS
+	NATSURL             string
+	NATSSubject         string
+	NATSToken           string
+	NATSCredentialsFile string
+	NATSTLS             bool
+	NATSJetStream       bool
+	NATS                bool
+
+	// Datadog
+	DatadogSite           string
+	DatadogAPIKey         string
+	DatadogTags           []string
+	DatadogAggregationKey string
+	Datadog               bool
+
+	// Sentry
+	SentryDSN         string
+	SentryEnvironment string
+	SentryTags        map[string]string
+	SentryFingerprint []string
+	Sentry            bool
+
+	// Custom webhook
+	CustomWebhookURL          string
+	CustomWebhookMethod       string
+	CustomWebhookHeaders      map[string]string
+	CustomWebhookBodyTemplate string
+	CustomWebhook             bool
+
+	// SES
+	SESRegion           string
+	SESAccessKeyID      string
+	SESSecretAccessKey  string
+	SESFrom             string
+	SESTo               []string
+	SESSubjectTemplate  string
+	SESHTMLTemplate     string
+	SESConfigurationSet string
+	SES                 bool
+
+	// Nextcloud Talk
+	NextcloudTalkURL         string
+	NextcloudTalkUsername    string
+	NextcloudTalkAppPassword string
+	NextcloudTalkRoomToken   string
+	NextcloudTalk            bool
+
+	// ServiceURLs are shoutrrr style urls, eg. discord://token@id,
+	// delivered through the native providers
+	ServiceURLs []string
+
+	// Proxy is the http, https or socks5 proxy url of the http based providers
+	Proxy string
+	// ProviderProxies overrides the proxy by provider name, an empty url connects directly
+	ProviderProxies map[string]string
+
+	// Retry is the retry policy of the http based providers
+	Retry RetryPolicy
+	// ProviderRetries overrides the retry policy by provider name
+	ProviderRetries map[string]RetryPolicy
+
+	// CircuitBreaker stops delivering to failing providers for a cooldown
+	CircuitBreaker CircuitBreaker
+	// ProviderCircuitBreakers overrides the circuit breaker by provider name
+	ProviderCircuitBreakers map[string]CircuitBreaker
+
+	// Templates are text/template rendering the messages by provider name with
+	// TemplateData, eg. "*{{.Severity}}* on {{.Hostname}}\n{{.Data}}" for slack
+	Templates map[string]string
+
+	// RateLimits paces the deliveries by provider name, eg. 1 message per
+	// second for slack webhooks, messages wait for their turn
+	RateLimits map[string]RateLimit
+
+	// BatchFlushInterval periodically writes the pending batches of the sinks
+	// with a batch size (s3, elasticsearch, splunk, clickhouse), defaults to
+	// DefaultBatchFlushInterval and a negative value writes them only once
+	// full or on Close
+	BatchFlushInterval time.Duration
+
+	// Capture intercepts provider requests for inspection instead of sending them
+	Capture bool
+	// CaptureForwardURL receives the captured requests, eg. a local request bin.
+	// The payloads of providers not using http are only recorded.
+	CaptureForwardURL string
+
+	// CoalesceWindow merges the messages of a source enqueued within the window,
+	// the combined notification is sent once the window or CoalesceMaxMessages is reached
+	CoalesceWindow time.Duration
+	// CoalesceMaxMessages flushes a source early, defaults to DefaultCoalesceMaxMessages
+	CoalesceMaxMessages int
+
+	// MessageTTL discards queued messages not delivered in time, 0 disables expiry
+	MessageTTL time.Duration
+
+	// QueueSize is the capacity of the async queue used by Enqueue
+	QueueSize int
+	// QueueWorkers deliver the queued messages concurrently, more than one
+	// worker doesn't preserve the order of the messages. Defaults to 1.
+	QueueWorkers int
+	// QueueMaxBytes bounds the memory used by queued messages, 0 means unbounded
+	QueueMaxBytes int64
+	// QueuePath persists the async queue so undelivered messages survive restarts
+	QueuePath string
+	// Notifiers enables the notifiers added with Register by name
+	Notifiers []string
 }
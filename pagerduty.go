@@ -0,0 +1,179 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// DefaultPagerDutyTimeout to conclude operations
+const DefaultPagerDutyTimeout = 5 * time.Second
+
+// PagerDutyEventsEndpoint of the events api v2
+const PagerDutyEventsEndpoint = "https://events.pagerduty.com/v2/enqueue"
+
+// pagerDutyMaxSummary is the longest summary accepted by the events api
+const pagerDutyMaxSummary = 1024
+
+// PagerDuty event actions
+const (
+	PagerDutyTrigger     = "trigger"
+	PagerDutyAcknowledge = "acknowledge"
+	PagerDutyResolve     = "resolve"
+)
+
+// PagerDuty event severities
+const (
+	PagerDutyCritical = "critical"
+	PagerDutyError    = "error"
+	PagerDutyWarning  = "warning"
+	PagerDutyInfo     = "info"
+)
+
+// PagerDutyClient sends events to a pagerduty service integration
+type PagerDutyClient struct {
+	client *retryablehttp.Client
+	// RoutingKey is the integration key of the service
+	RoutingKey string
+	// Source is the affected system, the hostname by default
+	Source string
+	// DedupKey groups the triggered events in a single incident
+	DedupKey string
+	TimeOut  time.Duration
+}
+
+// PagerDutyEvent json structure
+type PagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key,omitempty"`
+	Payload     *PagerDutyPayload `json:"payload,omitempty"`
+}
+
+// PagerDutyPayload of trigger events
+type PagerDutyPayload struct {
+	Summary       string                 `json:"summary"`
+	Source        string                 `json:"source"`
+	Severity      string                 `json:"severity"`
+	Timestamp     string                 `json:"timestamp,omitempty"`
+	Component     string                 `json:"component,omitempty"`
+	Group         string                 `json:"group,omitempty"`
+	Class         string                 `json:"class,omitempty"`
+	CustomDetails map[string]interface{} `json:"custom_details,omitempty"`
+}
+
+// pagerDutyResponse of the events api
+type pagerDutyResponse struct {
+	Status   string `json:"status"`
+	Message  string `json:"message"`
+	DedupKey string `json:"dedup_key"`
+}
+
+// SendInfo to pagerduty as info event
+func (pc *PagerDutyClient) SendInfo(message string) error {
+	return pc.SendInfoCtx(context.Background(), message)
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (pc *PagerDutyClient) SendInfoCtx(ctx context.Context, message string) error {
+	_, err := pc.TriggerCtx(ctx, message, PagerDutyInfo, pc.DedupKey)
+	return err
+}
+
+// SendWarning to pagerduty as warning event
+func (pc *PagerDutyClient) SendWarning(message string) error {
+	_, err := pc.TriggerCtx(context.Background(), message, PagerDutyWarning, pc.DedupKey)
+	return err
+}
+
+// SendError to pagerduty as critical event opening an incident
+func (pc *PagerDutyClient) SendError(message string) error {
+	_, err := pc.TriggerCtx(context.Background(), message, PagerDutyCritical, pc.DedupKey)
+	return err
+}
+
+// Trigger an event returning its dedup key, generated by pagerduty when empty
+func (pc *PagerDutyClient) Trigger(message, severity, dedupKey string) (string, error) {
+	return pc.TriggerCtx(context.Background(), message, severity, dedupKey)
+}
+
+// TriggerCtx is Trigger canceled with the context
+func (pc *PagerDutyClient) TriggerCtx(ctx context.Context, message, severity, dedupKey string) (string, error) {
+	source := pc.Source
+	if source == "" {
+		if source, _ = os.Hostname(); source == "" {
+			source = "notify"
+		}
+	}
+	summary := message
+	if runes := []rune(summary); len(runes) > pagerDutyMaxSummary {
+		summary = string(runes[:pagerDutyMaxSummary])
+	}
+	return pc.SendPagerDutyEventCtx(ctx, &PagerDutyEvent{
+		RoutingKey:  pc.RoutingKey,
+		EventAction: PagerDutyTrigger,
+		DedupKey:    dedupKey,
+		Payload: &PagerDutyPayload{
+			Summary:       summary,
+			Source:        source,
+			Severity:      severity,
+			Timestamp:     time.Now().UTC().Format(time.RFC3339),
+			CustomDetails: map[string]interface{}{"message": message},
+		},
+	})
+}
+
+// Acknowledge the incident of the dedup key
+func (pc *PagerDutyClient) Acknowledge(dedupKey string) error {
+	_, err := pc.SendPagerDutyEventCtx(context.Background(), &PagerDutyEvent{RoutingKey: pc.RoutingKey, EventAction: PagerDutyAcknowledge, DedupKey: dedupKey})
+	return err
+}
+
+// Resolve the incident of the dedup key
+func (pc *PagerDutyClient) Resolve(dedupKey string) error {
+	_, err := pc.SendPagerDutyEventCtx(context.Background(), &PagerDutyEvent{RoutingKey: pc.RoutingKey, EventAction: PagerDutyResolve, DedupKey: dedupKey})
+	return err
+}
+
+// SendPagerDutyEvent with json structure returning the dedup key
+func (pc *PagerDutyClient) SendPagerDutyEvent(event *PagerDutyEvent) (string, error) {
+	return pc.SendPagerDutyEventCtx(context.Background(), event)
+}
+
+// SendPagerDutyEventCtx is SendPagerDutyEvent canceled with the context
+func (pc *PagerDutyClient) SendPagerDutyEventCtx(ctx context.Context, event *PagerDutyEvent) (string, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return "", err
+	}
+	req, err := retryablehttp.NewRequest(http.MethodPost, PagerDutyEventsEndpoint, body)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Add("Content-Type", "application/json")
+
+	resp, err := doTimeout(pc.client, req.WithContext(ctx), pc.TimeOut)
+	if err != nil {
+		return "", err
+	}
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	//nolint:errcheck // silent fail
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return "", newResponseError(resp, buf)
+	}
+	var response pagerDutyResponse
+	if err := json.Unmarshal(buf, &response); err != nil {
+		return "", err
+	}
+	return response.DedupKey, nil
+}
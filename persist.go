@@ -0,0 +1,285 @@
+package notify
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// QueueFormatVersion is the version of the persisted queue format written by this release
+const QueueFormatVersion = 1
+
+// queueFormat identifies the persisted queue files
+const queueFormat = "notify-queue"
+
+// maxQueueRecordSize bounds a single line of the persisted queue
+const maxQueueRecordSize = 64 << 20
+
+// queueCompactAcks is the number of acknowledged messages after which the
+// log is rewritten with the pending ones only
+const queueCompactAcks = 1024
+
+// persisted queue operations
+const (
+	walEnqueue = "enqueue"
+	walAck     = "ack"
+)
+
+// ErrQueueVersion is returned for persisted queues written by a newer release
+var ErrQueueVersion = errors.New("persisted queue was written by a newer version")
+
+// queueMigrations upgrade a record of version v, the key, to version v+1.
+// Bumping QueueFormatVersion requires registering the migration from the
+// previous version so queues written by older releases are never stranded.
+var queueMigrations = map[int]func(record map[string]interface{}) (map[string]interface{}, error){}
+
+// walHeader is the first line of the persisted queue
+type walHeader struct {
+	Format  string `json:"format"`
+	Version int    `json:"version"`
+}
+
+// walRecord is a line of the persisted queue
+type walRecord struct {
+	Op      string `json:"op"`
+	ID      uint64 `json:"id"`
+	Message string `json:"message,omitempty"`
+	// ExpiresAt is in unix nanoseconds, 0 for messages without ttl
+	ExpiresAt int64 `json:"expires_at,omitempty"`
+}
+
+// queueWAL is the append only log of the async queue, messages are
+// appended when enqueued and acknowledged once delivered or discarded
+type queueWAL struct {
+	sync.Mutex
+	path    string
+	file    *os.File
+	enc     *json.Encoder
+	nextID  uint64
+	pending map[uint64]walRecord
+	acked   int
+	// corrupt is the number of unreadable lines skipped on replay
+	corrupt int
+}
+
+// openQueueWAL replays the log at path returning the undelivered messages,
+// migrating older formats and compacting the log to the current version
+func openQueueWAL(path string) (*queueWAL, []queuedMessage, error) {
+	records, corrupt, err := readQueueWAL(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var nextID uint64
+	pending := make(map[uint64]int)
+	var ordered []walRecord
+	for _, record := range records {
+		if record.ID >= nextID {
+			nextID = record.ID + 1
+		}
+		switch record.Op {
+		case walEnqueue:
+			pending[record.ID] = len(ordered)
+			ordered = append(ordered, record)
+		case walAck:
+			if i, ok := pending[record.ID]; ok {
+				ordered[i].Op = walAck
+				delete(pending, record.ID)
+			}
+		}
+	}
+
+	w := &queueWAL{path: path, nextID: nextID, pending: make(map[uint64]walRecord), corrupt: corrupt}
+	var messages []queuedMessage
+	for _, record := range ordered {
+		if record.Op != walEnqueue {
+			continue
+		}
+		queued := queuedMessage{id: record.ID, message: record.Message}
+		if record.ExpiresAt > 0 {
+			queued.expiresAt = time.Unix(0, record.ExpiresAt)
+		}
+		messages = append(messages, queued)
+		w.pending[record.ID] = record
+	}
+	if err := w.compact(); err != nil {
+		return nil, nil, err
+	}
+	return w, messages, nil
+}
+
+// compact rewrites the log with the pending messages in enqueue order and
+// reopens it for appending, the caller must hold the lock once opened
+func (w *queueWAL) compact() error {
+	records := make([]walRecord, 0, len(w.pending))
+	for _, record := range w.pending {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].ID < records[j].ID })
+
+	if err := writeQueueWAL(w.path, records); err != nil {
+		return err
+	}
+	if w.file != nil {
+		//nolint:errcheck // replaced by the compacted log
+		w.file.Close()
+		w.file = nil
+	}
+	file, err := os.OpenFile(w.path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	w.file = file
+	w.enc = json.NewEncoder(file)
+	w.acked = 0
+	return nil
+}
+
+// readQueueWAL decodes the records of the log upgrading them to the current
+// version, returning the number of unreadable lines skipped
+func readQueueWAL(path string) ([]walRecord, int, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	//nolint:errcheck // read only
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), maxQueueRecordSize)
+	if !scanner.Scan() {
+		return nil, 0, scanner.Err()
+	}
+	var header walHeader
+	if err := json.Unmarshal(scanner.Bytes(), &header); err != nil || header.Format != queueFormat {
+		return nil, 0, fmt.Errorf("%s is not a notify queue", path)
+	}
+	if header.Version > QueueFormatVersion {
+		return nil, 0, fmt.Errorf("%w: %s has version %d, supported %d", ErrQueueVersion, path, header.Version, QueueFormatVersion)
+	}
+
+	var records []walRecord
+	var corrupt int
+	for scanner.Scan() {
+		var raw map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &raw); err != nil {
+			// the last line is truncated if the process died while appending
+			corrupt++
+			continue
+		}
+		for version := header.Version; version < QueueFormatVersion; version++ {
+			migrate, ok := queueMigrations[version]
+			if !ok {
+				return nil, 0, fmt.Errorf("no migration of %s from version %d", path, version)
+			}
+			if raw, err = migrate(raw); err != nil {
+				return nil, 0, err
+			}
+		}
+		buf, err := json.Marshal(raw)
+		if err != nil {
+			return nil, 0, err
+		}
+		var record walRecord
+		if err := json.Unmarshal(buf, &record); err != nil {
+			corrupt++
+			continue
+		}
+		records = append(records, record)
+	}
+	return records, corrupt, scanner.Err()
+}
+
+// writeQueueWAL atomically replaces the log with the records in the current format
+func writeQueueWAL(path string, records []walRecord) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(tmp)
+	err = enc.Encode(&walHeader{Format: queueFormat, Version: QueueFormatVersion})
+	for i := 0; err == nil && i < len(records); i++ {
+		err = enc.Encode(&records[i])
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		//nolint:errcheck // silent fail
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// append logs an enqueued message returning its id
+func (w *queueWAL) append(queued queuedMessage) (uint64, error) {
+	w.Lock()
+	defer w.Unlock()
+
+	record := walRecord{Op: walEnqueue, ID: w.nextID, Message: queued.message}
+	if !queued.expiresAt.IsZero() {
+		record.ExpiresAt = queued.expiresAt.UnixNano()
+	}
+	if w.file == nil {
+		return 0, os.ErrClosed
+	}
+	if err := w.enc.Encode(&record); err != nil {
+		return 0, err
+	}
+	w.nextID++
+	w.pending[record.ID] = record
+	return record.ID, nil
+}
+
+// ack logs the message as delivered or discarded, compacting the log once
+// enough messages were acknowledged
+func (w *queueWAL) ack(id uint64) {
+	w.Lock()
+	defer w.Unlock()
+
+	if w.file == nil {
+		return
+	}
+	delete(w.pending, id)
+	//nolint:errcheck // replayed at worst
+	w.enc.Encode(&walRecord{Op: walAck, ID: id})
+	if w.acked++; w.acked >= queueCompactAcks {
+		//nolint:errcheck // retried on the next ack, the log stays valid
+		w.compact()
+	}
+}
+
+// corruptRecords returns the number of unreadable lines skipped on replay
+func (w *queueWAL) corruptRecords() int {
+	w.Lock()
+	defer w.Unlock()
+
+	return w.corrupt
+}
+
+func (w *queueWAL) close() error {
+	w.Lock()
+	defer w.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+	if err := w.file.Sync(); err != nil {
+		return err
+	}
+	return w.file.Close()
+}
@@ -0,0 +1,121 @@
+package notify
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func tempQueuePath(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "notify-queue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return filepath.Join(dir, "queue.log"), func() {
+		//nolint:errcheck // silent fail
+		os.RemoveAll(dir)
+	}
+}
+
+func TestQueueWALReplayCorrupt(t *testing.T) {
+	path, cleanup := tempQueuePath(t)
+	defer cleanup()
+
+	expiresAt := time.Unix(0, 1600000000000000000)
+	log := strings.Join([]string{
+		`{"format":"notify-queue","version":1}`,
+		`{"op":"enqueue","id":0,"message":"delivered"}`,
+		`not json`,
+		`{"op":"enqueue","id":1,"message":"pending","expires_at":1600000000000000000}`,
+		`{"op":"ack","id":0}`,
+		`{"op":"enqueue","id":2,"mess`,
+	}, "\n")
+	if err := ioutil.WriteFile(path, []byte(log), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	w, messages, err := openQueueWAL(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.close() //nolint:errcheck // silent fail
+
+	if len(messages) != 1 || messages[0].id != 1 || messages[0].message != "pending" || !messages[0].expiresAt.Equal(expiresAt) {
+		t.Fatalf("replayed %+v, want the pending message 1", messages)
+	}
+	if corrupt := w.corruptRecords(); corrupt != 2 {
+		t.Errorf("corruptRecords() = %d, want 2", corrupt)
+	}
+	if id, err := w.append(queuedMessage{message: "next"}); err != nil || id != 2 {
+		t.Errorf("append() = %d, %v, want id 2", id, err)
+	}
+
+	// the replay compacted the log to the pending messages
+	records, corrupt, err := readQueueWAL(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if corrupt != 0 || len(records) != 2 || records[0].ID != 1 || records[1].ID != 2 {
+		t.Errorf("compacted log = %+v with %d corrupt, want messages 1 and 2", records, corrupt)
+	}
+}
+
+func TestQueueWALCompact(t *testing.T) {
+	path, cleanup := tempQueuePath(t)
+	defer cleanup()
+
+	w, _, err := openQueueWAL(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < queueCompactAcks+1; i++ {
+		id, err := w.append(queuedMessage{message: "message"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i > 0 {
+			w.ack(id)
+		}
+	}
+	if err := w.close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the acknowledgements were compacted away leaving the first message
+	records, _, err := readQueueWAL(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Op != walEnqueue || records[0].ID != 0 {
+		t.Errorf("log after %d acks = %d records, want message 0", queueCompactAcks, len(records))
+	}
+
+	w, messages, err := openQueueWAL(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.close() //nolint:errcheck // silent fail
+	if len(messages) != 1 || messages[0].id != 0 {
+		t.Errorf("replayed %+v, want message 0", messages)
+	}
+	// ids of acknowledged messages compacted away may be reused
+	if id, err := w.append(queuedMessage{message: "next"}); err != nil || id == 0 {
+		t.Errorf("append() = %d, %v, want an id after the pending message", id, err)
+	}
+}
+
+func TestQueueWALVersion(t *testing.T) {
+	path, cleanup := tempQueuePath(t)
+	defer cleanup()
+
+	if err := ioutil.WriteFile(path, []byte(`{"format":"notify-queue","version":99}`+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := openQueueWAL(path); !errors.Is(err, ErrQueueVersion) {
+		t.Errorf("openQueueWAL() = %v, want ErrQueueVersion", err)
+	}
+}
@@ -0,0 +1,77 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+var errInvalidProxy = errors.New("invalid proxy url")
+
+// proxyURL returns the proxy of the provider, the global one if it has none
+func (options *Options) proxyURL(provider string) string {
+	if proxy, ok := options.ProviderProxies[provider]; ok {
+		return proxy
+	}
+	return options.Proxy
+}
+
+// validateProxies checks the proxies are http, https or socks5 urls
+func validateProxies(options *Options) error {
+	proxies := map[string]string{"": options.Proxy}
+	for provider, proxy := range options.ProviderProxies {
+		proxies[provider] = proxy
+	}
+	for provider, proxy := range proxies {
+		if proxy == "" {
+			continue
+		}
+		if _, err := parseProxy(proxy); err != nil {
+			if provider == "" {
+				return err
+			}
+			return fmt.Errorf("%s: %w", provider, err)
+		}
+	}
+	return nil
+}
+
+func parseProxy(proxy string) (*url.URL, error) {
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return nil, errInvalidProxy
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	case "socks5h":
+		// the socks5 support of http.Transport already resolves the names on
+		// the proxy but doesn't know the socks5h scheme
+		u.Scheme = "socks5"
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, errInvalidProxy
+	}
+	return u, nil
+}
+
+// setProxy routes the requests of the client through the proxy url
+func setProxy(client *retryablehttp.Client, proxy string) {
+	u, err := parseProxy(proxy)
+	if err != nil {
+		// rejected by validateProxies
+		return
+	}
+	var transport *http.Transport
+	if current, ok := client.HTTPClient.Transport.(*http.Transport); ok {
+		transport = current.Clone()
+	} else {
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	}
+	transport.Proxy = http.ProxyURL(u)
+	client.HTTPClient.Transport = transport
+}
@@ -0,0 +1,146 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// DefaultPushoverTimeout to conclude operations
+const DefaultPushoverTimeout = 5 * time.Second
+
+// PushoverEndpoint of the message api
+const PushoverEndpoint = "https://api.pushover.net/1/messages.json"
+
+// Pushover priorities
+const (
+	PushoverPriorityLowest    = -2
+	PushoverPriorityLow       = -1
+	PushoverPriorityNormal    = 0
+	PushoverPriorityHigh      = 1
+	PushoverPriorityEmergency = 2
+)
+
+// pushover emergency messages are repeated until acknowledged
+const (
+	pushoverEmergencyRetry  = 60
+	pushoverEmergencyExpire = 3600
+)
+
+// PushoverClient handling pushover notifications
+type PushoverClient struct {
+	client *retryablehttp.Client
+	// Token of the pushover application
+	Token string
+	// UserKey of the user or group receiving notifications
+	UserKey string
+	// Device restricts delivery to the named devices of the user
+	Device   string
+	Priority int
+	Sound    string
+	TimeOut  time.Duration
+}
+
+// PushoverMessage structure
+type PushoverMessage struct {
+	Message  string
+	Title    string
+	Priority int
+	Sound    string
+	// URL is a supplementary url shown with the message
+	URL      string
+	URLTitle string
+	HTML     bool
+}
+
+// SendInfo to pushover with the client priority
+func (pc *PushoverClient) SendInfo(message string) error {
+	return pc.SendInfoCtx(context.Background(), message)
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (pc *PushoverClient) SendInfoCtx(ctx context.Context, message string) error {
+	return pc.SendPushoverNotificationCtx(ctx, &PushoverMessage{Message: message, Priority: pc.Priority})
+}
+
+// SendWarning to pushover with high priority
+func (pc *PushoverClient) SendWarning(message string) error {
+	return pc.SendPushoverNotificationCtx(context.Background(), &PushoverMessage{Message: message, Priority: PushoverPriorityHigh})
+}
+
+// SendError to pushover with emergency priority, repeated until acknowledged
+func (pc *PushoverClient) SendError(message string) error {
+	return pc.SendPushoverNotificationCtx(context.Background(), &PushoverMessage{Message: message, Priority: PushoverPriorityEmergency})
+}
+
+// SendPushoverNotification with the message structure
+func (pc *PushoverClient) SendPushoverNotification(pushoverMessage *PushoverMessage) error {
+	return pc.SendPushoverNotificationCtx(context.Background(), pushoverMessage)
+}
+
+// SendPushoverNotificationCtx is SendPushoverNotification canceled with the context
+func (pc *PushoverClient) SendPushoverNotificationCtx(ctx context.Context, pushoverMessage *PushoverMessage) error {
+	if pushoverMessage.Priority < PushoverPriorityLowest || pushoverMessage.Priority > PushoverPriorityEmergency {
+		return fmt.Errorf("pushover priority %d out of range", pushoverMessage.Priority)
+	}
+	values := url.Values{
+		"token":   {pc.Token},
+		"user":    {pc.UserKey},
+		"message": {pushoverMessage.Message},
+	}
+	if pc.Device != "" {
+		values.Set("device", pc.Device)
+	}
+	if pushoverMessage.Title != "" {
+		values.Set("title", pushoverMessage.Title)
+	}
+	if pushoverMessage.Priority != PushoverPriorityNormal {
+		values.Set("priority", strconv.Itoa(pushoverMessage.Priority))
+	}
+	if pushoverMessage.Priority == PushoverPriorityEmergency {
+		values.Set("retry", strconv.Itoa(pushoverEmergencyRetry))
+		values.Set("expire", strconv.Itoa(pushoverEmergencyExpire))
+	}
+	sound := pushoverMessage.Sound
+	if sound == "" {
+		sound = pc.Sound
+	}
+	if sound != "" {
+		values.Set("sound", sound)
+	}
+	if pushoverMessage.URL != "" {
+		values.Set("url", pushoverMessage.URL)
+		values.Set("url_title", pushoverMessage.URLTitle)
+	}
+	if pushoverMessage.HTML {
+		values.Set("html", "1")
+	}
+
+	req, err := retryablehttp.NewRequest(http.MethodPost, PushoverEndpoint, []byte(values.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := doTimeout(pc.client, req.WithContext(ctx), pc.TimeOut)
+	if err != nil {
+		return err
+	}
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck // silent fail
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newResponseError(resp, buf)
+	}
+	return nil
+}
@@ -0,0 +1,285 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"runtime/pprof"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultQueueSize is the number of messages buffered for async delivery
+const DefaultQueueSize = 1000
+
+// DefaultQueueWorkers is the number of goroutines delivering queued messages
+const DefaultQueueWorkers = 1
+
+var (
+	// ErrQueueFull is returned when the async queue cannot accept more messages
+	ErrQueueFull = errors.New("notification queue is full")
+	// ErrQueueBudgetExceeded is returned when the queued messages would exceed the memory budget
+	ErrQueueBudgetExceeded = errors.New("notification queue memory budget exceeded")
+	// ErrClosed is returned when enqueuing on a closed notifier
+	ErrClosed = errors.New("notifier is closed")
+	// ErrExpired is reported for queued messages discarded past their ttl
+	ErrExpired = errors.New("notification expired")
+)
+
+// queuedMessage is a message waiting for async delivery
+type queuedMessage struct {
+	// id of the message in the persisted queue
+	id      uint64
+	message string
+	// expiresAt is the zero time for messages without ttl
+	expiresAt time.Time
+}
+
+// asyncQueue buffers messages delivered in background
+type asyncQueue struct {
+	// bytes is accessed atomically and must stay 64-bit aligned
+	bytes    int64
+	maxBytes int64
+
+	sync.RWMutex
+	messages  chan queuedMessage
+	wal       *queueWAL
+	closed    bool
+	workers   int
+	startOnce sync.Once
+	wg        sync.WaitGroup
+	// closing rejects new messages while the pending bursts are flushed
+	closing bool
+}
+
+func newAsyncQueue(size, workers int, maxBytes int64) *asyncQueue {
+	if size <= 0 {
+		size = DefaultQueueSize
+	}
+	if workers <= 0 {
+		workers = DefaultQueueWorkers
+	}
+	return &asyncQueue{messages: make(chan queuedMessage, size), workers: workers, maxBytes: maxBytes}
+}
+
+// reserve accounts size bytes to the queue if they fit in the budget
+func (q *asyncQueue) reserve(size int64) bool {
+	for {
+		current := atomic.LoadInt64(&q.bytes)
+		if q.maxBytes > 0 && current+size > q.maxBytes {
+			return false
+		}
+		if atomic.CompareAndSwapInt64(&q.bytes, current, current+size) {
+			return true
+		}
+	}
+}
+
+func (q *asyncQueue) release(size int64) {
+	atomic.AddInt64(&q.bytes, -size)
+}
+
+// isClosing reports whether the queue stopped accepting messages
+func (q *asyncQueue) isClosing() bool {
+	q.RLock()
+	defer q.RUnlock()
+	return q.closing || q.closed
+}
+
+// Enqueue schedules a message for asynchronous delivery to registered webhooks.
+// The call never blocks, if the queue is full or the memory budget is
+// exhausted the message is dropped.
+func (n *Notify) Enqueue(message string) error {
+	return n.EnqueueSource("", message)
+}
+
+// EnqueueTTL schedules a message discarded if not delivered within ttl,
+// so that stale alerts are not delivered late after an outage
+func (n *Notify) EnqueueTTL(message string, ttl time.Duration) error {
+	if n.queue.isClosing() {
+		return ErrClosed
+	}
+	return n.enqueueTTL(message, ttl)
+}
+
+// EnqueueSource schedules a message of the source for asynchronous delivery.
+// With a coalesce window configured, messages of the same source arriving
+// within the window are delivered as a single combined notification.
+func (n *Notify) EnqueueSource(source, message string) error {
+	if n.queue.isClosing() {
+		return ErrClosed
+	}
+	if n.coalescer == nil {
+		return n.enqueue(message)
+	}
+	n.coalescer.add(source, message)
+	return nil
+}
+
+func (n *Notify) enqueue(message string) error {
+	var ttl time.Duration
+	if n.options != nil {
+		ttl = n.options.MessageTTL
+	}
+	return n.enqueueTTL(message, ttl)
+}
+
+func (n *Notify) enqueueTTL(message string, ttl time.Duration) error {
+	n.queue.RLock()
+	defer n.queue.RUnlock()
+
+	if n.queue.closed {
+		return ErrClosed
+	}
+	n.startWorkers()
+
+	queued := queuedMessage{message: message}
+	if ttl > 0 {
+		queued.expiresAt = time.Now().Add(ttl)
+	}
+	size := int64(len(message))
+	if !n.queue.reserve(size) {
+		return n.drop(message, ErrQueueBudgetExceeded)
+	}
+	if n.queue.wal != nil {
+		id, err := n.queue.wal.append(queued)
+		if err != nil {
+			n.queue.release(size)
+			return n.drop(message, err)
+		}
+		queued.id = id
+	}
+	select {
+	case n.queue.messages <- queued:
+		n.events.publish(&Event{Type: EventEnqueued, Message: message})
+		return nil
+	default:
+		n.queue.release(size)
+		n.ack(queued)
+		return n.drop(message, ErrQueueFull)
+	}
+}
+
+// restore queues the messages left undelivered in the persisted queue
+func (n *Notify) restore(messages []queuedMessage) {
+	n.startWorkers()
+	for _, queued := range messages {
+		if !n.queue.reserve(int64(len(queued.message))) {
+			n.ack(queued)
+			//nolint:errcheck // accounted by stats and events
+			n.drop(queued.message, ErrQueueBudgetExceeded)
+			continue
+		}
+		n.queue.messages <- queued
+		n.events.publish(&Event{Type: EventEnqueued, Message: queued.message})
+	}
+}
+
+func (n *Notify) startWorkers() {
+	n.queue.startOnce.Do(func() {
+		n.queue.wg.Add(n.queue.workers)
+		for id := 0; id < n.queue.workers; id++ {
+			go n.worker(id)
+		}
+	})
+}
+
+// ack marks the message as done in the persisted queue
+func (n *Notify) ack(queued queuedMessage) {
+	if n.queue.wal != nil {
+		n.queue.wal.ack(queued.id)
+	}
+}
+
+// expire dead-letters a message which stayed in the queue past its ttl
+func (n *Notify) expire(message string) {
+	for _, p := range n.enabledProviders() {
+		n.stats.dropped(p.name)
+		n.events.publish(&Event{Type: EventDeadLettered, Provider: p.name, Message: message, Error: ErrExpired})
+	}
+}
+
+// drop accounts a message rejected by the queue to every enabled provider
+func (n *Notify) drop(message string, reason error) error {
+	for _, p := range n.enabledProviders() {
+		n.stats.dropped(p.name)
+		n.events.publish(&Event{Type: EventDropped, Provider: p.name, Message: message, Error: reason})
+	}
+	return reason
+}
+
+// worker delivers queued messages, the goroutine is tagged with pprof
+// labels so profiles can be attributed to the worker and provider
+func (n *Notify) worker(id int) {
+	defer n.queue.wg.Done()
+
+	labels := pprof.Labels("notify_worker", strconv.Itoa(id))
+	pprof.Do(context.Background(), labels, func(ctx context.Context) {
+		for queued := range n.queue.messages {
+			n.queue.release(int64(len(queued.message)))
+			if !queued.expiresAt.IsZero() && time.Now().After(queued.expiresAt) {
+				n.expire(queued.message)
+				n.ack(queued)
+				continue
+			}
+			//nolint:errcheck // outcome is tracked by stats and events
+			n.deliver(ctx, queued.message, true)
+			n.ack(queued)
+		}
+	})
+}
+
+// Close stops accepting messages, waits for queued ones to be delivered
+// and flushes the batches of the sinks
+func (n *Notify) Close() {
+	n.queue.Lock()
+	if n.queue.closing {
+		n.queue.Unlock()
+		return
+	}
+	n.queue.closing = true
+	n.queue.Unlock()
+
+	// the coalesced messages are queued before closing the channel
+	if n.coalescer != nil {
+		n.coalescer.flush()
+	}
+
+	n.queue.Lock()
+	n.queue.closed = true
+	close(n.queue.messages)
+	n.queue.Unlock()
+
+	n.queue.wg.Wait()
+
+	if n.queue.wal != nil {
+		//nolint:errcheck // silent fail
+		n.queue.wal.close()
+	}
+
+	n.approvals.stop()
+
+	if n.flusher != nil {
+		n.flusher.close()
+	}
+
+	n.flush()
+}
+
+// flush writes the pending messages of batching sinks
+func (n *Notify) flush() {
+	if n.options == nil {
+		return
+	}
+	for _, p := range n.enabledProviders() {
+		if p.flush == nil {
+			continue
+		}
+		if err := p.flush(); err != nil {
+			err = withProvider(p.name, err)
+			n.stats.record(p.name, err)
+			n.events.publish(&Event{Type: EventFailed, Provider: p.name, Error: err})
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package notify
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// maxLimiterKeys triggers the cleanup of expired keys
+const maxLimiterKeys = 1000
+
+// keyedLimiter spaces the operations sharing the same key by an interval
+type keyedLimiter struct {
+	mutex sync.Mutex
+	next  map[string]time.Time
+}
+
+// reserve books the next slot of the key and returns how long to wait for it
+func (l *keyedLimiter) reserve(key string, interval time.Duration) time.Duration {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := time.Now()
+	if l.next == nil {
+		l.next = make(map[string]time.Time)
+	}
+	if len(l.next) > maxLimiterKeys {
+		for k, next := range l.next {
+			if next.Before(now) {
+				delete(l.next, k)
+			}
+		}
+	}
+	next := l.next[key]
+	if next.Before(now) {
+		next = now
+	}
+	l.next[key] = next.Add(interval)
+	return next.Sub(now)
+}
+
+// wait blocks until the key can be used again or the context is done
+func (l *keyedLimiter) wait(ctx context.Context, key string, interval time.Duration) error {
+	if interval <= 0 {
+		return nil
+	}
+	delay := l.reserve(key, interval)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RateLimit of a provider, Rate messages per second with bursts of Burst messages
+type RateLimit struct {
+	Rate float64
+	// Burst defaults to 1
+	Burst int
+}
+
+// tokenBucket paces the deliveries of a provider, waiting callers reserve
+// tokens in advance so they are served in order
+type tokenBucket struct {
+	mutex  sync.Mutex
+	limit  RateLimit
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(limit RateLimit) *tokenBucket {
+	if limit.Burst <= 0 {
+		limit.Burst = 1
+	}
+	return &tokenBucket{limit: limit, tokens: float64(limit.Burst), last: time.Now()}
+}
+
+// reserve takes a token and returns how long to wait for it
+func (b *tokenBucket) reserve() time.Duration {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.limit.Rate
+	if burst := float64(b.limit.Burst); b.tokens > burst {
+		b.tokens = burst
+	}
+	b.last = now
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.limit.Rate * float64(time.Second))
+}
+
+// cancel returns a reserved token
+func (b *tokenBucket) cancel() {
+	b.mutex.Lock()
+	b.tokens++
+	b.mutex.Unlock()
+}
+
+// wait blocks until a token is available or the context is done
+func (b *tokenBucket) wait(ctx context.Context) error {
+	delay := b.reserve()
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		b.cancel()
+		return ctx.Err()
+	}
+}
+
+// newRateLimiters returns the buckets of the providers with a positive rate
+func newRateLimiters(limits map[string]RateLimit) map[string]*tokenBucket {
+	limiters := make(map[string]*tokenBucket, len(limits))
+	for name, limit := range limits {
+		if limit.Rate > 0 {
+			limiters[name] = newTokenBucket(limit)
+		}
+	}
+	return limiters
+}
@@ -0,0 +1,73 @@
+package notify
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// within reports whether d is want up to the refill of the test run
+func within(d, want time.Duration) bool {
+	return d <= want && d > want-10*time.Millisecond
+}
+
+func TestTokenBucketReserve(t *testing.T) {
+	bucket := newTokenBucket(RateLimit{Rate: 10, Burst: 3})
+	for i := 0; i < 3; i++ {
+		if delay := bucket.reserve(); delay != 0 {
+			t.Fatalf("reserve() of burst token %d = %s, want 0", i, delay)
+		}
+	}
+	// waiting callers are queued a token interval apart
+	for i, want := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond} {
+		if delay := bucket.reserve(); !within(delay, want) {
+			t.Errorf("reserve() %d over the burst = %s, want %s", i, delay, want)
+		}
+	}
+	bucket.cancel()
+	if delay := bucket.reserve(); !within(delay, 200*time.Millisecond) {
+		t.Errorf("reserve() after cancel = %s, want the canceled slot", delay)
+	}
+}
+
+func TestTokenBucketRefill(t *testing.T) {
+	bucket := newTokenBucket(RateLimit{Rate: 10, Burst: 2})
+	bucket.reserve()
+	bucket.reserve()
+	// a second refills the bucket up to the burst only
+	bucket.last = bucket.last.Add(-time.Second)
+	for i := 0; i < 2; i++ {
+		if delay := bucket.reserve(); delay != 0 {
+			t.Errorf("reserve() %d after refill = %s, want 0", i, delay)
+		}
+	}
+	if delay := bucket.reserve(); delay == 0 {
+		t.Error("reserve() over the refilled burst did not wait")
+	}
+}
+
+func TestTokenBucketWaitCanceled(t *testing.T) {
+	bucket := newTokenBucket(RateLimit{Rate: 1})
+	if err := bucket.wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := bucket.wait(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("wait() = %v, want %v", err, context.DeadlineExceeded)
+	}
+	// the canceled wait gave its token back, else the delay would be 2s
+	if delay := bucket.reserve(); delay > time.Second || delay < 900*time.Millisecond {
+		t.Errorf("reserve() after a canceled wait = %s, want under a second", delay)
+	}
+}
+
+func TestNewRateLimiters(t *testing.T) {
+	limiters := newRateLimiters(map[string]RateLimit{"slack": {Rate: 1}, "discord": {}})
+	if len(limiters) != 1 || limiters["slack"] == nil {
+		t.Errorf("newRateLimiters() = %v, want slack only", limiters)
+	}
+	if burst := limiters["slack"].limit.Burst; burst != 1 {
+		t.Errorf("default burst = %d, want 1", burst)
+	}
+}
@@ -0,0 +1,47 @@
+package notify
+
+// Render returns the payload every enabled provider would send for the
+// message, keyed by provider name, without delivering anything.
+// Providers carrying the message in the query string are rendered as the request url.
+func (n *Notify) Render(message string) (map[string][]byte, error) {
+	rendered := make(map[string][]byte)
+	if n.options == nil {
+		// no provider is enabled without options
+		return rendered, nil
+	}
+	options := *n.options
+	options.Capture = true
+	options.CaptureForwardURL = ""
+	// render every member of the failover groups
+	options.FailoverGroups = nil
+	// the shadow notifier must not touch the persisted queue, deliver through
+	// the registered notifiers or wait on pacing, bursts and confirmations
+	options.QueuePath = ""
+	options.Notifiers = nil
+	options.RateLimits = nil
+	options.CoalesceWindow = 0
+	options.CoalesceMaxMessages = 0
+	options.MessageTTL = 0
+	options.ConfirmDelivery = false
+
+	shadow, err := NewWithOptions(&options)
+	if err != nil {
+		return nil, err
+	}
+	defer shadow.Close()
+
+	if err := shadow.SendNotification(message); err != nil {
+		return nil, err
+	}
+	shadow.flush()
+
+	// the last request of a provider is the delivery, previous ones are lookups
+	for _, capture := range shadow.Captures() {
+		if capture.Body != "" {
+			rendered[capture.Provider] = []byte(capture.Body)
+		} else {
+			rendered[capture.Provider] = []byte(capture.URL)
+		}
+	}
+	return rendered, nil
+}
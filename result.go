@@ -0,0 +1,58 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	// confirmAttempts is the number of lookups done to confirm a delivery
+	confirmAttempts = 3
+	// confirmDelay is the wait between confirmation lookups
+	confirmDelay = time.Second
+)
+
+// ErrNotConfirmed is returned when a delivered message cannot be found afterwards
+var ErrNotConfirmed = errors.New("delivery could not be confirmed")
+
+// DeliveryResult describes a message accepted by a provider, the providers
+// without delivery details only set the Provider and Timestamp
+type DeliveryResult struct {
+	Provider string
+	// MessageID identifies the message on the provider, if supported
+	MessageID string
+	Channel   string
+	// Timestamp is the time the provider accepted the message
+	Timestamp time.Time
+	// StatusCode of the http response, 0 for other transports
+	StatusCode int
+	// Confirmed is set once the message was fetched back from the provider
+	Confirmed bool
+}
+
+func newDeliveryResult(provider string, statusCode int) *DeliveryResult {
+	return &DeliveryResult{Provider: provider, Timestamp: time.Now(), StatusCode: statusCode}
+}
+
+// poll runs the lookup until it finds the message, the attempts are
+// exhausted or the context is done
+func poll(ctx context.Context, lookup func() (bool, error)) (bool, error) {
+	var err error
+	for attempt := 0; attempt < confirmAttempts; attempt++ {
+		if attempt > 0 {
+			timer := time.NewTimer(confirmDelay)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return false, ctx.Err()
+			}
+		}
+		var found bool
+		if found, err = lookup(); found {
+			return true, nil
+		}
+	}
+	return false, err
+}
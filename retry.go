@@ -0,0 +1,179 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// RetryBackoff is the wait strategy between the attempts of a request
+type RetryBackoff string
+
+// Retry backoffs
+const (
+	// RetryBackoffExponential doubles the wait between attempts. It's the default.
+	RetryBackoffExponential RetryBackoff = "exponential"
+	// RetryBackoffLinearJitter waits a random time growing linearly with the attempts
+	RetryBackoffLinearJitter RetryBackoff = "linear-jitter"
+	// RetryBackoffFullJitter waits a random time up to the exponential wait
+	RetryBackoffFullJitter RetryBackoff = "full-jitter"
+	// RetryBackoffExponentialJitter adds a random time to the exponential wait
+	RetryBackoffExponentialJitter RetryBackoff = "exponential-jitter"
+)
+
+// RetryPolicy of the http requests of a provider, zero values keep the defaults
+type RetryPolicy struct {
+	// MaxRetries after the first attempt, negative disables retries
+	MaxRetries int
+	WaitMin    time.Duration
+	WaitMax    time.Duration
+	Backoff    RetryBackoff
+	// RetryableStatusCodes replaces the default of retrying the 5xx responses,
+	// connection errors are retried regardless
+	RetryableStatusCodes []int
+}
+
+// retryPolicy returns the policy of the provider, the global one if it has none
+func (options *Options) retryPolicy(provider string) RetryPolicy {
+	if policy, ok := options.ProviderRetries[provider]; ok {
+		return policy
+	}
+	return options.Retry
+}
+
+// validateRetries checks the backoffs of the retry policies are known
+func validateRetries(options *Options) error {
+	policies := map[string]RetryPolicy{"": options.Retry}
+	for provider, policy := range options.ProviderRetries {
+		policies[provider] = policy
+	}
+	for provider, policy := range policies {
+		if _, err := policy.backoff(); err != nil {
+			if provider == "" {
+				return err
+			}
+			return fmt.Errorf("%s: %w", provider, err)
+		}
+	}
+	return nil
+}
+
+// options returns the client options of the policy
+func (p RetryPolicy) options() retryablehttp.Options {
+	options := retryablehttp.DefaultOptionsSingle
+	switch {
+	case p.MaxRetries < 0:
+		options.RetryMax = 0
+	case p.MaxRetries > 0:
+		options.RetryMax = p.MaxRetries
+	}
+	if p.WaitMin > 0 {
+		options.RetryWaitMin = p.WaitMin
+	}
+	if p.WaitMax > 0 {
+		options.RetryWaitMax = p.WaitMax
+	}
+	return options
+}
+
+func (p RetryPolicy) backoff() (retryablehttp.Backoff, error) {
+	switch p.Backoff {
+	case "", RetryBackoffExponential:
+		return retryablehttp.DefaultBackoff(), nil
+	case RetryBackoffLinearJitter:
+		return retryablehttp.LinearJitterBackoff(), nil
+	case RetryBackoffFullJitter:
+		return retryablehttp.FullJitterBackoff(), nil
+	case RetryBackoffExponentialJitter:
+		return retryablehttp.ExponentialJitterBackoff(), nil
+	default:
+		return nil, fmt.Errorf("unknown retry backoff %q", p.Backoff)
+	}
+}
+
+// attemptTimeoutKey carries the timeout of the attempts of a request
+type attemptTimeoutKey struct{}
+
+// doTimeout sends the request bounding each attempt, the read of the
+// response body included, by the timeout of the provider
+func doTimeout(client *retryablehttp.Client, req *retryablehttp.Request, timeout time.Duration) (*http.Response, error) {
+	if timeout > 0 {
+		req = req.WithContext(context.WithValue(req.Context(), attemptTimeoutKey{}, timeout))
+	}
+	return client.Do(req)
+}
+
+// socketDeadline bounds the exchange of the providers not using http by the
+// timeout, or the deadline of the context when it expires first
+func socketDeadline(ctx context.Context, timeout time.Duration) time.Time {
+	deadline := time.Now().Add(timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		return ctxDeadline
+	}
+	return deadline
+}
+
+// attemptTimeoutTransport applies the timeout set by doTimeout to every attempt
+type attemptTimeoutTransport struct {
+	next http.RoundTripper
+}
+
+func (t *attemptTimeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	timeout, ok := req.Context().Value(attemptTimeoutKey{}).(time.Duration)
+	if !ok {
+		return next.RoundTrip(req)
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	resp, err := next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelBody releases the timeout of an attempt once its body is closed
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+// newRetryClient returns a client retrying according to the policy
+func newRetryClient(p RetryPolicy) *retryablehttp.Client {
+	client := retryablehttp.NewClient(p.options())
+	if backoff, err := p.backoff(); err == nil {
+		client.Backoff = backoff
+	}
+	if len(p.RetryableStatusCodes) == 0 {
+		return client
+	}
+	retryable := make(map[int]bool, len(p.RetryableStatusCodes))
+	for _, code := range p.RetryableStatusCodes {
+		retryable[code] = true
+	}
+	checkRetry := retryablehttp.DefaultRetryPolicy()
+	client.CheckRetry = func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		if err != nil || resp == nil {
+			return checkRetry(ctx, resp, err)
+		}
+		return retryable[resp.StatusCode], nil
+	}
+	return client
+}
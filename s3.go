@@ -0,0 +1,126 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// DefaultS3Timeout to conclude operations
+const DefaultS3Timeout = 10 * time.Second
+
+// S3Client archives notifications as objects in a S3 compatible bucket
+type S3Client struct {
+	client *retryablehttp.Client
+	// Endpoint of S3 compatible services (eg. http://minio:9000), defaults to aws
+	Endpoint    string
+	Region      string
+	Bucket      string
+	Prefix      string
+	Credentials AWSCredentials
+	// PathStyle addresses the bucket in the path instead of the hostname
+	PathStyle bool
+	// BatchSize rolls up that many messages into a single ndjson object
+	BatchSize int
+	TimeOut   time.Duration
+
+	batch recordBatch
+}
+
+// SendInfo archives the message, with batching enabled it is written once the batch is full
+func (sc *S3Client) SendInfo(message string) error {
+	return sc.SendInfoCtx(context.Background(), message)
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (sc *S3Client) SendInfoCtx(ctx context.Context, message string) error {
+	if records := sc.batch.add(newRecord(message), sc.BatchSize); len(records) > 0 {
+		return sc.putRecords(ctx, records)
+	}
+	return nil
+}
+
+// Flush writes the pending batched messages
+func (sc *S3Client) Flush() error {
+	if records := sc.batch.drain(); len(records) > 0 {
+		return sc.putRecords(context.Background(), records)
+	}
+	return nil
+}
+
+func (sc *S3Client) putRecords(ctx context.Context, records []record) error {
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+
+	extension := ".json"
+	if len(records) > 1 {
+		extension = ".ndjson"
+	}
+	// the random suffix keeps objects of concurrent writers from overwriting each other
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return err
+	}
+	first := records[0].Time
+	key := fmt.Sprintf("%s%s/%d-%s%s", sc.Prefix, first.Format("2006/01/02"), first.UnixNano(), hex.EncodeToString(suffix), extension)
+	return sc.putObject(ctx, key, body.Bytes())
+}
+
+func (sc *S3Client) objectURL(key string) string {
+	endpoint := sc.Endpoint
+	if endpoint == "" {
+		endpoint = "https://s3." + sc.Region + ".amazonaws.com"
+	}
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	if sc.PathStyle {
+		return endpoint + "/" + sc.Bucket + "/" + key
+	}
+	scheme := "https://"
+	if strings.HasPrefix(endpoint, "http://") {
+		scheme = "http://"
+	}
+	return scheme + sc.Bucket + "." + strings.TrimPrefix(strings.TrimPrefix(endpoint, "https://"), "http://") + "/" + key
+}
+
+func (sc *S3Client) putObject(ctx context.Context, key string, body []byte) error {
+	req, err := retryablehttp.NewRequest(http.MethodPut, sc.objectURL(key), body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	credentials, err := sc.Credentials.resolve()
+	if err != nil {
+		return err
+	}
+	signAWSRequest(req.Request, body, "s3", sc.Region, credentials, time.Now())
+
+	resp, err := doTimeout(sc.client, req.WithContext(ctx), sc.TimeOut)
+	if err != nil {
+		return err
+	}
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck // silent fail
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newResponseError(resp, buf)
+	}
+	return nil
+}
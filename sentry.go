@@ -0,0 +1,178 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// DefaultSentryTimeout to conclude operations
+const DefaultSentryTimeout = 5 * time.Second
+
+// Sentry levels of the severity helpers
+const (
+	SentryLevelInfo    = "info"
+	SentryLevelWarning = "warning"
+	SentryLevelError   = "error"
+	SentryLevelFatal   = "fatal"
+)
+
+// ErrInvalidSentryDSN is returned for dsn without key or project
+var ErrInvalidSentryDSN = errors.New("invalid sentry dsn")
+
+// SentryClient captures notifications as events through the envelope endpoint
+type SentryClient struct {
+	client *retryablehttp.Client
+	// DSN of the project, eg. https://key@o0.ingest.sentry.io/42
+	DSN         string
+	Environment string
+	Tags        map[string]string
+	// Fingerprint groups the events, {{ default }} extends the default grouping
+	Fingerprint []string
+	TimeOut     time.Duration
+}
+
+// SentryEvent json structure
+type SentryEvent struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   float64           `json:"timestamp"`
+	Level       string            `json:"level"`
+	Logger      string            `json:"logger,omitempty"`
+	Platform    string            `json:"platform"`
+	ServerName  string            `json:"server_name,omitempty"`
+	Environment string            `json:"environment,omitempty"`
+	Message     *SentryMessage    `json:"message"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	Fingerprint []string          `json:"fingerprint,omitempty"`
+}
+
+// SentryMessage of an event
+type SentryMessage struct {
+	Formatted string `json:"formatted"`
+}
+
+// SendInfo to sentry
+func (sc *SentryClient) SendInfo(message string) error {
+	return sc.SendInfoCtx(context.Background(), message)
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (sc *SentryClient) SendInfoCtx(ctx context.Context, message string) error {
+	return sc.CaptureCtx(ctx, SentryLevelInfo, message, sc.Fingerprint)
+}
+
+// SendWarning to sentry
+func (sc *SentryClient) SendWarning(message string) error {
+	return sc.CaptureCtx(context.Background(), SentryLevelWarning, message, sc.Fingerprint)
+}
+
+// SendError to sentry
+func (sc *SentryClient) SendError(message string) error {
+	return sc.CaptureCtx(context.Background(), SentryLevelError, message, sc.Fingerprint)
+}
+
+// Capture sends the message as event of the level grouped by the fingerprint
+func (sc *SentryClient) Capture(level, message string, fingerprint []string) error {
+	return sc.CaptureCtx(context.Background(), level, message, fingerprint)
+}
+
+// CaptureCtx is Capture canceled with the context
+func (sc *SentryClient) CaptureCtx(ctx context.Context, level, message string, fingerprint []string) error {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return err
+	}
+	host, _ := os.Hostname()
+	return sc.SendSentryEventCtx(ctx, &SentryEvent{
+		EventID:     hex.EncodeToString(id),
+		Timestamp:   float64(time.Now().UnixNano()) / float64(time.Second),
+		Level:       level,
+		Logger:      "notify",
+		Platform:    "other",
+		ServerName:  host,
+		Environment: sc.Environment,
+		Message:     &SentryMessage{Formatted: message},
+		Tags:        sc.Tags,
+		Fingerprint: fingerprint,
+	})
+}
+
+// SendSentryEvent with json structure
+func (sc *SentryClient) SendSentryEvent(event *SentryEvent) error {
+	return sc.SendSentryEventCtx(context.Background(), event)
+}
+
+// SendSentryEventCtx is SendSentryEvent canceled with the context
+func (sc *SentryClient) SendSentryEventCtx(ctx context.Context, event *SentryEvent) error {
+	endpoint, auth, err := parseSentryDSN(sc.DSN)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	// the envelope is the header, the item header and the event on separate lines
+	header, err := json.Marshal(map[string]string{"event_id": event.EventID, "dsn": sc.DSN})
+	if err != nil {
+		return err
+	}
+	var body bytes.Buffer
+	body.Write(header)
+	body.WriteString("\n{\"type\":\"event\"}\n")
+	body.Write(payload)
+	body.WriteString("\n")
+
+	req, err := retryablehttp.NewRequest(http.MethodPost, endpoint, body.Bytes())
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/x-sentry-envelope")
+	req.Header.Add("X-Sentry-Auth", auth)
+
+	resp, err := doTimeout(sc.client, req.WithContext(ctx), sc.TimeOut)
+	if err != nil {
+		return err
+	}
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck // silent fail
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return newResponseError(resp, buf)
+	}
+	return nil
+}
+
+// parseSentryDSN returns the envelope endpoint and the auth header of the dsn
+func parseSentryDSN(dsn string) (string, string, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return "", "", err
+	}
+	path := strings.TrimSuffix(u.Path, "/")
+	i := strings.LastIndexByte(path, '/')
+	if u.User == nil || u.User.Username() == "" || i < 0 || i == len(path)-1 {
+		return "", "", ErrInvalidSentryDSN
+	}
+	auth := "Sentry sentry_version=7, sentry_client=notify/1.0, sentry_key=" + u.User.Username()
+	if secret, ok := u.User.Password(); ok {
+		auth += ", sentry_secret=" + secret
+	}
+	endpoint := u.Scheme + "://" + u.Host + path[:i] + "/api/" + path[i+1:] + "/envelope/"
+	return endpoint, auth, nil
+}
@@ -0,0 +1,146 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// DefaultSESTimeout to conclude operations
+const DefaultSESTimeout = 10 * time.Second
+
+// DefaultSESHTMLTemplate is the html body template used when none is configured
+const DefaultSESHTMLTemplate = "<pre>{{.Message}}</pre>"
+
+// SESClient sends notifications by mail with the aws ses v2 api
+type SESClient struct {
+	client *retryablehttp.Client
+	// Region defaults to AWS_REGION
+	Region string
+	// Credentials fall back to the default aws chain when empty
+	Credentials AWSCredentials
+	From        string
+	To          []string
+	// SubjectTemplate is a text/template rendered with EmailSubjectData
+	SubjectTemplate string
+	// HTMLTemplate is an html/template rendered with EmailSubjectData,
+	// DefaultSESHTMLTemplate if empty
+	HTMLTemplate string
+	// ConfigurationSet tracks the deliveries, bounces and complaints
+	ConfigurationSet string
+	TimeOut          time.Duration
+}
+
+// SESEmail json structure of the v2 SendEmail action
+type SESEmail struct {
+	FromEmailAddress string `json:"FromEmailAddress"`
+	Destination      struct {
+		ToAddresses []string `json:"ToAddresses"`
+	} `json:"Destination"`
+	Content struct {
+		Simple struct {
+			Subject SESContent `json:"Subject"`
+			Body    struct {
+				Text *SESContent `json:"Text,omitempty"`
+				HTML *SESContent `json:"Html,omitempty"`
+			} `json:"Body"`
+		} `json:"Simple"`
+	} `json:"Content"`
+	ConfigurationSetName string `json:"ConfigurationSetName,omitempty"`
+}
+
+// SESContent is a text with its charset
+type SESContent struct {
+	Data    string `json:"Data"`
+	Charset string `json:"Charset,omitempty"`
+}
+
+// SendInfo with ses
+func (sc *SESClient) SendInfo(message string) error {
+	return sc.SendInfoCtx(context.Background(), message)
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (sc *SESClient) SendInfoCtx(ctx context.Context, message string) error {
+	return sc.send(ctx, "info", message)
+}
+
+// SendWarning with ses
+func (sc *SESClient) SendWarning(message string) error {
+	return sc.send(context.Background(), "warning", message)
+}
+
+// SendError with ses
+func (sc *SESClient) SendError(message string) error {
+	return sc.send(context.Background(), "error", message)
+}
+
+func (sc *SESClient) send(ctx context.Context, severity, message string) error {
+	subject, err := renderEmailSubject(sc.SubjectTemplate, severity, message)
+	if err != nil {
+		return err
+	}
+	text := sc.HTMLTemplate
+	if text == "" {
+		text = DefaultSESHTMLTemplate
+	}
+	html, err := renderEmailHTML(text, severity, message)
+	if err != nil {
+		return err
+	}
+
+	email := &SESEmail{FromEmailAddress: sc.From, ConfigurationSetName: sc.ConfigurationSet}
+	email.Destination.ToAddresses = sc.To
+	email.Content.Simple.Subject = SESContent{Data: subject, Charset: "UTF-8"}
+	email.Content.Simple.Body.Text = &SESContent{Data: message, Charset: "UTF-8"}
+	email.Content.Simple.Body.HTML = &SESContent{Data: html, Charset: "UTF-8"}
+	return sc.SendSESEmailCtx(ctx, email)
+}
+
+// SendSESEmail with json structure
+func (sc *SESClient) SendSESEmail(email *SESEmail) error {
+	return sc.SendSESEmailCtx(context.Background(), email)
+}
+
+// SendSESEmailCtx is SendSESEmail canceled with the context
+func (sc *SESClient) SendSESEmailCtx(ctx context.Context, email *SESEmail) error {
+	body, err := json.Marshal(email)
+	if err != nil {
+		return err
+	}
+	region := sc.Region
+	if region == "" {
+		region = os.Getenv("AWS_REGION")
+	}
+	req, err := retryablehttp.NewRequest(http.MethodPost, "https://email."+region+".amazonaws.com/v2/email/outbound-emails", body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	credentials, err := sc.Credentials.resolve()
+	if err != nil {
+		return err
+	}
+	signAWSRequest(req.Request, body, "ses", region, credentials, time.Now())
+
+	resp, err := doTimeout(sc.client, req.WithContext(ctx), sc.TimeOut)
+	if err != nil {
+		return err
+	}
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck // silent fail
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newResponseError(resp, buf)
+	}
+	return nil
+}
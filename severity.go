@@ -0,0 +1,71 @@
+package notify
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Severities of the SendInfo, SendWarning and SendError helpers
+const (
+	SeverityInfo    = "info"
+	SeverityWarning = "warning"
+	SeverityError   = "error"
+)
+
+// SeverityStyle is the rendering of a severity
+type SeverityStyle struct {
+	// Color is a hex rgb color, eg. #2eb886
+	Color string
+	// Emoji is the slack icon emoji, eg. :rotating_light:
+	Emoji string
+	// Label titles the messages when set, eg. Critical
+	Label string
+}
+
+// SeverityScheme maps the severities to their style
+type SeverityScheme map[string]SeverityStyle
+
+// DefaultSeverityScheme matches the slack attachment colors
+var DefaultSeverityScheme = SeverityScheme{
+	SeverityInfo:    {Color: "#2eb886", Emoji: ":hammer_and_wrench:"},
+	SeverityWarning: {Color: "#daa038", Emoji: ":hammer_and_wrench:"},
+	SeverityError:   {Color: "#a30200", Emoji: ":hammer_and_wrench:"},
+}
+
+// Style of the severity, unset values fall back to the default scheme
+func (s SeverityScheme) Style(severity string) SeverityStyle {
+	style := s[severity]
+	def := DefaultSeverityScheme[severity]
+	if style.Color == "" {
+		style.Color = def.Color
+	}
+	if style.Emoji == "" {
+		style.Emoji = def.Emoji
+	}
+	if style.Label == "" {
+		style.Label = def.Label
+	}
+	return style
+}
+
+// hexColor returns the color without the leading #
+func (s SeverityStyle) hexColor() string {
+	return strings.TrimPrefix(s.Color, "#")
+}
+
+// intColor returns the color as a number, 0 when invalid
+func (s SeverityStyle) intColor() int {
+	color, err := strconv.ParseInt(s.hexColor(), 16, 32)
+	if err != nil {
+		return 0
+	}
+	return int(color)
+}
+
+// labeled prefixes the message with the label of the style
+func (s SeverityStyle) labeled(message string) string {
+	if s.Label == "" {
+		return message
+	}
+	return "**" + s.Label + "**\n" + message
+}
@@ -0,0 +1,107 @@
+package notify
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// DefaultSignalTimeout to conclude operations
+const DefaultSignalTimeout = 10 * time.Second
+
+// SignalClient sends messages through a signal-cli-rest-api instance
+type SignalClient struct {
+	client *retryablehttp.Client
+	// URL of the signal-cli-rest-api instance
+	URL string
+	// Number is the registered sender number
+	Number string
+	// Recipients are phone numbers or group ids as returned by /v1/groups
+	Recipients []string
+	TimeOut    time.Duration
+}
+
+// SignalMessage json structure of the v2 send api
+type SignalMessage struct {
+	Message    string   `json:"message"`
+	Number     string   `json:"number"`
+	Recipients []string `json:"recipients"`
+	// Base64Attachments are data uris of the attached files
+	Base64Attachments []string `json:"base64_attachments,omitempty"`
+}
+
+// SignalAttachment is a file sent with a message
+type SignalAttachment struct {
+	Filename string
+	Data     []byte
+}
+
+// SendInfo to signal
+func (sc *SignalClient) SendInfo(message string) error {
+	return sc.SendInfoCtx(context.Background(), message)
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (sc *SignalClient) SendInfoCtx(ctx context.Context, message string) error {
+	return sc.SendAttachmentsCtx(ctx, message)
+}
+
+// SendAttachments to signal with the message as caption
+func (sc *SignalClient) SendAttachments(message string, attachments ...SignalAttachment) error {
+	return sc.SendAttachmentsCtx(context.Background(), message, attachments...)
+}
+
+// SendAttachmentsCtx is SendAttachments canceled with the context
+func (sc *SignalClient) SendAttachmentsCtx(ctx context.Context, message string, attachments ...SignalAttachment) error {
+	signalMessage := &SignalMessage{Message: message, Number: sc.Number, Recipients: sc.Recipients}
+	for _, attachment := range attachments {
+		contentType := http.DetectContentType(attachment.Data)
+		if i := strings.Index(contentType, ";"); i > 0 {
+			contentType = contentType[:i]
+		}
+		signalMessage.Base64Attachments = append(signalMessage.Base64Attachments,
+			"data:"+contentType+";filename="+filepath.Base(attachment.Filename)+";base64,"+base64.StdEncoding.EncodeToString(attachment.Data))
+	}
+	return sc.SendSignalNotificationCtx(ctx, signalMessage)
+}
+
+// SendSignalNotification with json structure
+func (sc *SignalClient) SendSignalNotification(signalMessage *SignalMessage) error {
+	return sc.SendSignalNotificationCtx(context.Background(), signalMessage)
+}
+
+// SendSignalNotificationCtx is SendSignalNotification canceled with the context
+func (sc *SignalClient) SendSignalNotificationCtx(ctx context.Context, signalMessage *SignalMessage) error {
+	body, err := json.Marshal(signalMessage)
+	if err != nil {
+		return err
+	}
+	req, err := retryablehttp.NewRequest(http.MethodPost, strings.TrimSuffix(sc.URL, "/")+"/v2/send", body)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/json")
+
+	resp, err := doTimeout(sc.client, req.WithContext(ctx), sc.TimeOut)
+	if err != nil {
+		return err
+	}
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck // silent fail
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return newResponseError(resp, buf)
+	}
+	return nil
+}
@@ -4,10 +4,17 @@ package notify
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/projectdiscovery/retryablehttp-go"
@@ -16,146 +23,818 @@ import (
 // DefaultSlackTimeout to conclude operations
 const DefaultSlackTimeout = 5 * time.Second
 
+// DefaultSlackChannelInterval is the minimum delay between messages to a channel
+const DefaultSlackChannelInterval = time.Second
+
+// SlackAPIEndpoint is the base url of the web api methods
+const SlackAPIEndpoint = "https://slack.com/api/"
+
+// SlackPostMessageEndpoint of the web api
+const SlackPostMessageEndpoint = SlackAPIEndpoint + "chat.postMessage"
+
+// ErrSlackTokenRequired is returned by features only available with a bot token
+var ErrSlackTokenRequired = errors.New("slack bot token required")
+
+// Error codes of slack responses, see SlackError
+const (
+	SlackErrInvalidPayload   = "invalid_payload"
+	SlackErrChannelNotFound  = "channel_not_found"
+	SlackErrNotInChannel     = "not_in_channel"
+	SlackErrIsArchived       = "is_archived"
+	SlackErrInvalidAuth      = "invalid_auth"
+	SlackErrNoService        = "no_service"
+	SlackErrActionProhibited = "action_prohibited"
+	SlackErrMessageTooLong   = "msg_too_long"
+	SlackErrRateLimited      = "ratelimited"
+)
+
+// SlackError is the error code returned by the web api or a webhook,
+// use errors.As to inspect it
+type SlackError struct {
+	Code string
+}
+
+// Error returns the slack error code
+func (e *SlackError) Error() string {
+	return "slack: " + e.Code
+}
+
+// Is maps the slack codes to the kinds of delivery errors
+func (e *SlackError) Is(target error) bool {
+	switch e.Code {
+	case SlackErrInvalidAuth, "not_authed", "token_revoked", "account_inactive", SlackErrNoService:
+		return target == ErrUnauthorized
+	case SlackErrRateLimited, "rate_limited":
+		return target == ErrRateLimited
+	case SlackErrMessageTooLong:
+		return target == ErrPayloadTooLarge
+	}
+	return false
+}
+
+// IsSlackError reports whether the error is the slack error code
+func IsSlackError(err error, code string) bool {
+	var slackErr *SlackError
+	return errors.As(err, &slackErr) && slackErr.Code == code
+}
+
+// SlackMode selects the transport and payload format
+type SlackMode string
+
+// Slack modes, SlackModeAuto detects it from the credential
+const (
+	SlackModeAuto     SlackMode = ""
+	SlackModeWebhook  SlackMode = "webhook"
+	SlackModeWorkflow SlackMode = "workflow"
+	SlackModeToken    SlackMode = "token"
+)
+
 // SlackClient holding the slack communication logic
 type SlackClient struct {
 	client     *retryablehttp.Client
 	WebHookURL string
-	UserName   string
-	Channel    string
-	TimeOut    time.Duration
+	// WebHookURLs are used instead of WebHookURL according to WebHookStrategy
+	WebHookURLs     []string
+	WebHookStrategy WebhookStrategy
+	// Severities overrides the colors, emojis and labels of the severity helpers
+	Severities SeverityScheme
+	// Token is a bot token used with the web api
+	Token    string
+	UserName string
+	Channel  string
+	// IconURL is the default avatar of the messages, takes precedence over emojis
+	IconURL string
+	// Mode overrides the detected transport
+	Mode SlackMode
+	// ThreadTS posts the messages as replies in the thread of that message
+	ThreadTS string
+	// DisableUnfurl prevents url previews, eg. for long lists of urls
+	DisableUnfurl bool
+	// ChannelInterval spaces messages to the same channel, defaults to
+	// DefaultSlackChannelInterval and a negative value disables it
+	ChannelInterval time.Duration
+	TimeOut         time.Duration
+
+	channelLimiter keyedLimiter
+	webhooks       webhookPool
+
+	// userIDs caches the ids resolved from emails
+	userIDsMutex sync.RWMutex
+	userIDs      map[string]string
+}
+
+// DetectSlackMode guesses the transport from a webhook url or token
+func DetectSlackMode(credential string) SlackMode {
+	switch {
+	case strings.HasPrefix(credential, "xox"):
+		return SlackModeToken
+	case strings.Contains(credential, "/workflows/") || strings.Contains(credential, "/triggers/"):
+		return SlackModeWorkflow
+	default:
+		return SlackModeWebhook
+	}
+}
+
+// mode returns the explicit mode or the detected one
+func (sc *SlackClient) mode() SlackMode {
+	if sc.Mode != SlackModeAuto {
+		return sc.Mode
+	}
+	if sc.Token != "" {
+		return SlackModeToken
+	}
+	return DetectSlackMode(webhookURLs(sc.WebHookURL, sc.WebHookURLs)[0])
+}
+
+// token returns the bot token, which may be configured as the webhook url
+func (sc *SlackClient) token() string {
+	if sc.Token != "" {
+		return sc.Token
+	}
+	return sc.WebHookURL
+}
+
+// SlackAPIResponse is the envelope of web api responses
+type SlackAPIResponse struct {
+	Ok    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
 }
 
 // SimpleSlackRequest basic request
 type SimpleSlackRequest struct {
 	Text      string
 	IconEmoji string
+	IconURL   string
+	// Channel overrides the client channel
+	Channel string
 }
 
 // SlackJobNotification structure
 type SlackJobNotification struct {
 	Color     string
+	Title     string
 	IconEmoji string
+	IconURL   string
 	Details   string
 	Text      string
+	// Channel overrides the client channel
+	Channel string
+	// Fields are metadata displayed as a table
+	Fields map[string]string
 }
 
 // SlackMessage structure
 type SlackMessage struct {
-	Username    string       `json:"username,omitempty"`
-	IconEmoji   string       `json:"icon_emoji,omitempty"`
-	Channel     string       `json:"channel,omitempty"`
+	Username  string `json:"username,omitempty"`
+	IconEmoji string `json:"icon_emoji,omitempty"`
+	IconURL   string `json:"icon_url,omitempty"`
+	Channel   string `json:"channel,omitempty"`
+	// User receives ephemeral messages
+	User        string       `json:"user,omitempty"`
 	Text        string       `json:"text,omitempty"`
 	Attachments []Attachment `json:"attachments,omitempty"`
+	Blocks      []SlackBlock `json:"blocks,omitempty"`
+	// ThreadTS is the ts of the parent message of a reply
+	ThreadTS string `json:"thread_ts,omitempty"`
+	// ReplyBroadcast also shows the reply in the channel
+	ReplyBroadcast bool `json:"reply_broadcast,omitempty"`
+	// UnfurlLinks and UnfurlMedia control the previews of urls, nil keeps the slack default
+	UnfurlLinks *bool `json:"unfurl_links,omitempty"`
+	UnfurlMedia *bool `json:"unfurl_media,omitempty"`
 }
 
+// SlackBlock is a block kit layout block
+type SlackBlock map[string]interface{}
+
 // Attachment of slack message
 type Attachment struct {
-	Color         string `json:"color,omitempty"`
-	Fallback      string `json:"fallback,omitempty"`
-	CallbackID    string `json:"callback_id,omitempty"`
-	ID            int    `json:"id,omitempty"`
-	AuthorID      string `json:"author_id,omitempty"`
-	AuthorName    string `json:"author_name,omitempty"`
-	AuthorSubname string `json:"author_subname,omitempty"`
-	AuthorLink    string `json:"author_link,omitempty"`
-	AuthorIcon    string `json:"author_icon,omitempty"`
-	Title         string `json:"title,omitempty"`
-	TitleLink     string `json:"title_link,omitempty"`
-	Pretext       string `json:"pretext,omitempty"`
-	Text          string `json:"text,omitempty"`
-	ImageURL      string `json:"image_url,omitempty"`
-	ThumbURL      string `json:"thumb_url,omitempty"`
-	// Fields and actions are not defined.
+	Color         string            `json:"color,omitempty"`
+	Fallback      string            `json:"fallback,omitempty"`
+	CallbackID    string            `json:"callback_id,omitempty"`
+	ID            int               `json:"id,omitempty"`
+	AuthorID      string            `json:"author_id,omitempty"`
+	AuthorName    string            `json:"author_name,omitempty"`
+	AuthorSubname string            `json:"author_subname,omitempty"`
+	AuthorLink    string            `json:"author_link,omitempty"`
+	AuthorIcon    string            `json:"author_icon,omitempty"`
+	Title         string            `json:"title,omitempty"`
+	TitleLink     string            `json:"title_link,omitempty"`
+	Pretext       string            `json:"pretext,omitempty"`
+	Text          string            `json:"text,omitempty"`
+	ImageURL      string            `json:"image_url,omitempty"`
+	ThumbURL      string            `json:"thumb_url,omitempty"`
+	Fields        []AttachmentField `json:"fields,omitempty"`
+	// Actions are not defined, use blocks for interactive messages.
 	MarkdownIn []string    `json:"mrkdwn_in,omitempty"`
 	TS         json.Number `json:"ts,omitempty"`
 }
 
+// AttachmentField is shown in a table, short fields side by side
+type AttachmentField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short,omitempty"`
+}
+
+// attachmentShortField is the longest value displayed in a column
+const attachmentShortField = 40
+
+// AttachmentFields returns the fields of the key values sorted by key,
+// short values are displayed in columns
+func AttachmentFields(values map[string]string) []AttachmentField {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	fields := make([]AttachmentField, 0, len(keys))
+	for _, key := range keys {
+		value := values[key]
+		fields = append(fields, AttachmentField{Title: key, Value: value, Short: len(value) <= attachmentShortField && !strings.Contains(value, "\n")})
+	}
+	return fields
+}
+
 // SendSlackNotification will post to an 'Incoming Webook' url setup in Slack Apps. It accepts
 // some text and the slack channel is saved within Slack.
 func (sc *SlackClient) SendSlackNotification(sr SimpleSlackRequest) error {
+	return sc.SendSlackNotificationCtx(context.Background(), sr)
+}
+
+// SendSlackNotificationCtx is SendSlackNotification canceled with the context
+func (sc *SlackClient) SendSlackNotificationCtx(ctx context.Context, sr SimpleSlackRequest) error {
 	slackRequest := &SlackMessage{
 		Text:      sr.Text,
 		Username:  sc.UserName,
 		IconEmoji: sr.IconEmoji,
-		Channel:   sc.Channel,
+		IconURL:   sc.iconURL(sr.IconURL),
+		Channel:   sc.channel(sr.Channel),
+	}
+	return sc.sendHTTPRequest(ctx, slackRequest)
+}
+
+// Broadcast posts the message to every channel, the first error is returned
+// once all were tried
+func (sc *SlackClient) Broadcast(channels []string, message string) error {
+	var firstErr error
+	for _, channel := range channels {
+		if err := sc.SendSlackNotification(SimpleSlackRequest{Text: message, Channel: channel}); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("%s: %w", channel, err)
+		}
 	}
-	return sc.sendHTTPRequest(slackRequest)
+	return firstErr
 }
 
 // SendJobNotification will post a job notification to slack
 func (sc *SlackClient) SendJobNotification(job SlackJobNotification) error {
+	return sc.SendJobNotificationCtx(context.Background(), job)
+}
+
+// SendJobNotificationCtx is SendJobNotification canceled with the context
+func (sc *SlackClient) SendJobNotificationCtx(ctx context.Context, job SlackJobNotification) error {
+	_, err := sc.sendJob(ctx, job)
+	return err
+}
+
+func (sc *SlackClient) sendJob(ctx context.Context, job SlackJobNotification) (*DeliveryResult, error) {
 	attachment := Attachment{
 		Color: job.Color,
+		Title: job.Title,
 		Text:  job.Details,
 		TS:    json.Number(strconv.FormatInt(time.Now().Unix(), 10)),
 	}
+	if len(job.Fields) > 0 {
+		attachment.Fields = AttachmentFields(job.Fields)
+	}
 	slackRequest := &SlackMessage{
 		Text:        job.Text,
 		Username:    sc.UserName,
 		IconEmoji:   job.IconEmoji,
-		Channel:     sc.Channel,
+		IconURL:     sc.iconURL(job.IconURL),
+		Channel:     sc.channel(job.Channel),
 		Attachments: []Attachment{attachment},
 	}
-	return sc.sendHTTPRequest(slackRequest)
+	return sc.send(ctx, slackRequest)
+}
+
+// SendEphemeral posts a message in the channel visible only to the user (bot token only)
+func (sc *SlackClient) SendEphemeral(user, message string) error {
+	return sc.SendEphemeralTo(sc.Channel, user, message)
+}
+
+// SendEphemeralTo posts a message in the channel, or the thread of the client,
+// visible only to the user (bot token only)
+func (sc *SlackClient) SendEphemeralTo(channel, user, message string) error {
+	if sc.mode() != SlackModeToken {
+		return ErrSlackTokenRequired
+	}
+	slackRequest := &SlackMessage{
+		Text:     message,
+		Username: sc.UserName,
+		IconURL:  sc.IconURL,
+		Channel:  sc.channel(channel),
+		User:     user,
+		ThreadTS: sc.ThreadTS,
+	}
+	ctx := context.Background()
+	if err := sc.waitChannel(ctx, slackRequest.Channel); err != nil {
+		return err
+	}
+	return sc.callAPI(ctx, "chat.postEphemeral", slackRequest, nil)
+}
+
+// LookupUserByEmail returns the id of the user registered with the email (bot token only)
+func (sc *SlackClient) LookupUserByEmail(email string) (string, error) {
+	if sc.mode() != SlackModeToken {
+		return "", ErrSlackTokenRequired
+	}
+	sc.userIDsMutex.RLock()
+	id, ok := sc.userIDs[email]
+	sc.userIDsMutex.RUnlock()
+	if ok {
+		return id, nil
+	}
+
+	var lookupResponse struct {
+		User struct {
+			ID string `json:"id"`
+		} `json:"user"`
+	}
+	if err := sc.callAPIForm(context.Background(), "users.lookupByEmail", url.Values{"email": {email}}, &lookupResponse); err != nil {
+		return "", err
+	}
+
+	sc.userIDsMutex.Lock()
+	if sc.userIDs == nil {
+		sc.userIDs = make(map[string]string)
+	}
+	sc.userIDs[email] = lookupResponse.User.ID
+	sc.userIDsMutex.Unlock()
+	return lookupResponse.User.ID, nil
+}
+
+// SlackMention returns the mention of the user id, notifying the user
+func SlackMention(userID string) string {
+	return "<@" + userID + ">"
+}
+
+// MentionEmails prefixes the message with the mentions of the users registered
+// with the emails, eg. to ping the on-call engineer (bot token only)
+func (sc *SlackClient) MentionEmails(message string, emails ...string) (string, error) {
+	mentions := make([]string, 0, len(emails)+1)
+	for _, email := range emails {
+		id, err := sc.LookupUserByEmail(email)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", email, err)
+		}
+		mentions = append(mentions, SlackMention(id))
+	}
+	return strings.Join(append(mentions, message), " "), nil
+}
+
+// SendDM delivers a direct message to the user registered with the email (bot token only)
+func (sc *SlackClient) SendDM(email, message string) error {
+	user, err := sc.LookupUserByEmail(email)
+	if err != nil {
+		return err
+	}
+	var openResponse struct {
+		Channel struct {
+			ID string `json:"id"`
+		} `json:"channel"`
+	}
+	if err := sc.callAPI(context.Background(), "conversations.open", map[string]string{"users": user}, &openResponse); err != nil {
+		return err
+	}
+	slackRequest := &SlackMessage{
+		Text:     message,
+		Username: sc.UserName,
+		IconURL:  sc.IconURL,
+		Channel:  openResponse.Channel.ID,
+	}
+	return sc.sendHTTPRequest(context.Background(), slackRequest)
 }
 
 // SendError message
 func (sc *SlackClient) SendError(message string, options ...string) (err error) {
-	return sc.funcName("danger", message, options)
+	return sc.funcName(context.Background(), SeverityError, message, options)
 }
 
 // SendInfo message
 func (sc *SlackClient) SendInfo(message string, options ...string) (err error) {
-	return sc.funcName("good", message, options)
+	return sc.funcName(context.Background(), SeverityInfo, message, options)
 }
 
 // SendWarning message
 func (sc *SlackClient) SendWarning(message string, options ...string) (err error) {
-	return sc.funcName("warning", message, options)
+	return sc.funcName(context.Background(), SeverityWarning, message, options)
+}
+
+// SendInfoResult sends the info message and describes its delivery,
+// the message id and channel are set with the bot token only
+func (sc *SlackClient) SendInfoResult(ctx context.Context, message string, options ...string) (*DeliveryResult, error) {
+	return sc.sendSeverity(ctx, SeverityInfo, message, options)
+}
+
+// SendErrorCtx is SendError canceled with the context
+func (sc *SlackClient) SendErrorCtx(ctx context.Context, message string, options ...string) error {
+	return sc.funcName(ctx, SeverityError, message, options)
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (sc *SlackClient) SendInfoCtx(ctx context.Context, message string, options ...string) error {
+	return sc.funcName(ctx, SeverityInfo, message, options)
 }
 
-func (sc *SlackClient) funcName(color, message string, options []string) error {
-	emoji := ":hammer_and_wrench"
+// SendWarningCtx is SendWarning canceled with the context
+func (sc *SlackClient) SendWarningCtx(ctx context.Context, message string, options ...string) error {
+	return sc.funcName(ctx, SeverityWarning, message, options)
+}
+
+// iconURL returns the message icon or the client default one
+func (sc *SlackClient) iconURL(iconURL string) string {
+	if iconURL != "" {
+		return iconURL
+	}
+	return sc.IconURL
+}
+
+// channel returns the message channel or the client default one
+func (sc *SlackClient) channel(channel string) string {
+	if channel != "" {
+		return channel
+	}
+	return sc.Channel
+}
+
+// funcName sends a job notification, the optional first option is an emoji or an icon url
+func (sc *SlackClient) funcName(ctx context.Context, severity, message string, options []string) error {
+	_, err := sc.sendSeverity(ctx, severity, message, options)
+	return err
+}
+
+// sendSeverity returns the result of the first chunk of the message
+func (sc *SlackClient) sendSeverity(ctx context.Context, severity, message string, options []string) (*DeliveryResult, error) {
+	style := sc.Severities.Style(severity)
+	emoji := style.Emoji
+	var iconURL string
 	if len(options) > 0 {
-		emoji = options[0]
+		if strings.HasPrefix(options[0], "http://") || strings.HasPrefix(options[0], "https://") {
+			iconURL = options[0]
+		} else {
+			emoji = options[0]
+		}
 	}
-	sjn := SlackJobNotification{
-		Color:     color,
-		IconEmoji: emoji,
-		Details:   message,
+	var result *DeliveryResult
+	for _, chunk := range ChunkMessage(message, SlackMaxMessageLength) {
+		sjn := SlackJobNotification{
+			Color:     style.Color,
+			Title:     style.Label,
+			IconEmoji: emoji,
+			IconURL:   iconURL,
+			Details:   chunk,
+		}
+		sent, err := sc.sendJob(ctx, sjn)
+		if err != nil {
+			return nil, err
+		}
+		if result == nil {
+			result = sent
+		}
 	}
-	return sc.SendJobNotification(sjn)
+	return result, nil
 }
 
-func (sc *SlackClient) sendHTTPRequest(slackRequest *SlackMessage) error {
-	slackBody, err := json.Marshal(slackRequest)
+// waitChannel enforces the per channel rate limit, messages without
+// channel are accounted to the default channel of the webhook
+func (sc *SlackClient) waitChannel(ctx context.Context, channel string) error {
+	interval := sc.ChannelInterval
+	if interval == 0 {
+		interval = DefaultSlackChannelInterval
+	}
+	if channel == "" {
+		channel = sc.WebHookURL
+	}
+	return sc.channelLimiter.wait(ctx, channel, interval)
+}
+
+func (sc *SlackClient) sendHTTPRequest(ctx context.Context, slackRequest *SlackMessage) error {
+	_, err := sc.send(ctx, slackRequest)
+	return err
+}
+
+func (sc *SlackClient) send(ctx context.Context, slackRequest *SlackMessage) (*DeliveryResult, error) {
+	if slackRequest.ThreadTS == "" {
+		slackRequest.ThreadTS = sc.ThreadTS
+	}
+	if sc.DisableUnfurl {
+		unfurl := false
+		if slackRequest.UnfurlLinks == nil {
+			slackRequest.UnfurlLinks = &unfurl
+		}
+		if slackRequest.UnfurlMedia == nil {
+			slackRequest.UnfurlMedia = &unfurl
+		}
+	}
+	if err := sc.waitChannel(ctx, slackRequest.Channel); err != nil {
+		return nil, err
+	}
+	switch sc.mode() {
+	case SlackModeToken:
+		return sc.postMessageResult(ctx, slackRequest)
+	case SlackModeWorkflow:
+		return sc.sendWorkflow(ctx, slackRequest)
+	default:
+		return sc.sendWebhook(ctx, slackRequest)
+	}
+}
+
+// sendWorkflow posts to a workflow builder webhook, which only accepts
+// flat variables instead of the message structure
+func (sc *SlackClient) sendWorkflow(ctx context.Context, slackRequest *SlackMessage) (*DeliveryResult, error) {
+	text := slackRequest.Text
+	for _, attachment := range slackRequest.Attachments {
+		if text != "" && attachment.Text != "" {
+			text += "\n"
+		}
+		text += attachment.Text
+	}
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return nil, err
+	}
+	return sc.postWebhooks(ctx, body)
+}
+
+// PostMessage sends the message with chat.postMessage and returns the channel and
+// ts of the posted message, needed to reply in its thread or update it (bot token only).
+// The unset username, icon and channel are the client ones.
+func (sc *SlackClient) PostMessage(slackRequest *SlackMessage) (*DeliveryResult, error) {
+	if sc.mode() != SlackModeToken {
+		return nil, ErrSlackTokenRequired
+	}
+	if slackRequest.Username == "" {
+		slackRequest.Username = sc.UserName
+	}
+	if slackRequest.IconURL == "" && slackRequest.IconEmoji == "" {
+		slackRequest.IconURL = sc.IconURL
+	}
+	if slackRequest.Channel == "" {
+		slackRequest.Channel = sc.Channel
+	}
+	ctx := context.Background()
+	if err := sc.waitChannel(ctx, slackRequest.Channel); err != nil {
+		return nil, err
+	}
+	return sc.postMessageResult(ctx, slackRequest)
+}
+
+// StartThread posts the message and returns its ts, follow-ups are posted
+// in its thread with Reply (bot token only)
+func (sc *SlackClient) StartThread(message string) (string, error) {
+	result, err := sc.PostMessage(&SlackMessage{Text: message})
 	if err != nil {
+		return "", err
+	}
+	return result.MessageID, nil
+}
+
+// Reply posts the message in the thread of the threadTS message
+func (sc *SlackClient) Reply(threadTS, message string) error {
+	return sc.sendHTTPRequest(context.Background(), &SlackMessage{
+		Text:     message,
+		Username: sc.UserName,
+		IconURL:  sc.IconURL,
+		Channel:  sc.Channel,
+		ThreadTS: threadTS,
+	})
+}
+
+// UpdateMessage replaces the text of the message posted at ts in the channel id,
+// eg. to turn a "running" message into the job result (bot token only)
+func (sc *SlackClient) UpdateMessage(channel, ts, message string) error {
+	if sc.mode() != SlackModeToken {
+		return ErrSlackTokenRequired
+	}
+	return sc.callAPI(context.Background(), "chat.update", map[string]string{"channel": channel, "ts": ts, "text": message}, nil)
+}
+
+// DeleteMessage removes the message posted at ts in the channel id (bot token only)
+func (sc *SlackClient) DeleteMessage(channel, ts string) error {
+	if sc.mode() != SlackModeToken {
+		return ErrSlackTokenRequired
+	}
+	return sc.callAPI(context.Background(), "chat.delete", map[string]string{"channel": channel, "ts": ts}, nil)
+}
+
+// SendAt schedules the message to be posted at t with chat.scheduleMessage,
+// messages due are posted immediately (bot token only)
+func (sc *SlackClient) SendAt(t time.Time, message string) error {
+	if sc.mode() != SlackModeToken {
+		return ErrSlackTokenRequired
+	}
+	slackRequest := &SlackMessage{Text: message, Channel: sc.Channel, ThreadTS: sc.ThreadTS}
+	if !t.After(time.Now()) {
+		return sc.sendHTTPRequest(context.Background(), slackRequest)
+	}
+	schedule := struct {
+		*SlackMessage
+		PostAt int64 `json:"post_at"`
+	}{SlackMessage: slackRequest, PostAt: t.Unix()}
+	return sc.callAPI(context.Background(), "chat.scheduleMessage", schedule, nil)
+}
+
+// NextWorkingHour returns t when it falls on a weekday between the start and
+// end hours, otherwise the next weekday at the start hour in the location of t
+func NextWorkingHour(t time.Time, startHour, endHour int) time.Time {
+	weekday := t.Weekday() != time.Saturday && t.Weekday() != time.Sunday
+	if weekday && t.Hour() >= startHour && t.Hour() < endHour {
+		return t
+	}
+	next := time.Date(t.Year(), t.Month(), t.Day(), startHour, 0, 0, 0, t.Location())
+	if !next.After(t) {
+		next = next.AddDate(0, 0, 1)
+	}
+	for next.Weekday() == time.Saturday || next.Weekday() == time.Sunday {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+func (sc *SlackClient) postMessageResult(ctx context.Context, slackRequest *SlackMessage) (*DeliveryResult, error) {
+	var postResponse struct {
+		Channel string `json:"channel"`
+		TS      string `json:"ts"`
+	}
+	if err := sc.callAPI(ctx, "chat.postMessage", slackRequest, &postResponse); err != nil {
+		return nil, err
+	}
+	result := newDeliveryResult(ProviderSlack, http.StatusOK)
+	result.MessageID, result.Channel = postResponse.TS, postResponse.Channel
+	return result, nil
+}
+
+// SendConfirmed posts the message and fetches it back from the channel history (bot token only)
+func (sc *SlackClient) SendConfirmed(message string) (*DeliveryResult, error) {
+	return sc.SendConfirmedCtx(context.Background(), message)
+}
+
+// SendConfirmedCtx is SendConfirmed canceled with the context
+func (sc *SlackClient) SendConfirmedCtx(ctx context.Context, message string) (*DeliveryResult, error) {
+	if sc.mode() != SlackModeToken {
+		return nil, ErrSlackTokenRequired
+	}
+	if err := sc.waitChannel(ctx, sc.Channel); err != nil {
+		return nil, err
+	}
+	result, err := sc.postMessageResult(ctx, &SlackMessage{
+		Text:     message,
+		Username: sc.UserName,
+		IconURL:  sc.IconURL,
+		Channel:  sc.Channel,
+	})
+	if err != nil {
+		return nil, err
+	}
+	values := url.Values{
+		"channel":   {result.Channel},
+		"latest":    {result.MessageID},
+		"inclusive": {"true"},
+		"limit":     {"1"},
+	}
+	result.Confirmed, err = poll(ctx, func() (bool, error) {
+		var history struct {
+			Messages []struct {
+				TS string `json:"ts"`
+			} `json:"messages"`
+		}
+		if err := sc.callAPIForm(ctx, "conversations.history", values, &history); err != nil {
+			return false, err
+		}
+		return len(history.Messages) > 0 && history.Messages[0].TS == result.MessageID, nil
+	})
+	return result, err
+}
+
+// callAPI invokes a web api method with a json payload, the response is
+// decoded in result when not nil
+func (sc *SlackClient) callAPI(ctx context.Context, method string, payload, result interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := retryablehttp.NewRequest(http.MethodPost, SlackAPIEndpoint+method, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/json; charset=utf-8")
+	return sc.doAPI(req.WithContext(ctx), result)
+}
+
+// callAPIForm invokes a web api method not accepting json payloads
+func (sc *SlackClient) callAPIForm(ctx context.Context, method string, values url.Values, result interface{}) error {
+	req, err := retryablehttp.NewRequest(http.MethodPost, SlackAPIEndpoint+method, []byte(values.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	return sc.doAPI(req.WithContext(ctx), result)
+}
+
+// doAPI authenticates the request and validates the web api envelope
+func (sc *SlackClient) doAPI(req *retryablehttp.Request, result interface{}) error {
+	req.Header.Add("Authorization", "Bearer "+sc.token())
+
+	resp, err := doTimeout(sc.client, req, sc.TimeOut)
+	if err != nil {
+		return err
+	}
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck // silent fail
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError {
+		return newResponseError(resp, buf)
+	}
+	var apiResponse SlackAPIResponse
+	if err := json.Unmarshal(buf, &apiResponse); err != nil {
 		return err
 	}
-	req, err := retryablehttp.NewRequest(http.MethodPost, sc.WebHookURL, bytes.NewBuffer(slackBody))
+	if !apiResponse.Ok {
+		return &SlackError{Code: apiResponse.Error}
+	}
+	if result != nil {
+		return json.Unmarshal(buf, result)
+	}
+	return nil
+}
+
+func (sc *SlackClient) sendWebhook(ctx context.Context, slackRequest *SlackMessage) (*DeliveryResult, error) {
+	slackBody, err := json.Marshal(slackRequest)
 	if err != nil {
+		return nil, err
+	}
+	return sc.postWebhooks(ctx, slackBody)
+}
+
+// postWebhooks posts to the webhook urls according to the strategy
+func (sc *SlackClient) postWebhooks(ctx context.Context, slackBody []byte) (*DeliveryResult, error) {
+	var statusCode int
+	err := sc.webhooks.send(webhookURLs(sc.WebHookURL, sc.WebHookURLs), sc.WebHookStrategy, func(URL string) (err error) {
+		statusCode, err = sc.post(ctx, URL, slackBody)
 		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newDeliveryResult(ProviderSlack, statusCode), nil
+}
+
+func (sc *SlackClient) post(ctx context.Context, URL string, slackBody []byte) (int, error) {
+	req, err := retryablehttp.NewRequest(http.MethodPost, URL, bytes.NewBuffer(slackBody))
+	if err != nil {
+		return 0, err
 	}
 	req.Header.Add("Content-Type", "application/json")
 	if sc.TimeOut == 0 {
 		sc.TimeOut = DefaultSlackTimeout
 	}
 
-	resp, err := sc.client.Do(req)
+	resp, err := doTimeout(sc.client, req.WithContext(ctx), sc.TimeOut)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	buf, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	//nolint:errcheck // silent fail
 	defer resp.Body.Close()
 
-	if string(buf) != ok {
-		return err
+	// webhooks answer ok or the error code, workflows a web api envelope
+	text := strings.TrimSpace(string(buf))
+	var apiResponse SlackAPIResponse
+	if json.Unmarshal(buf, &apiResponse) == nil {
+		if apiResponse.Ok && resp.StatusCode < http.StatusBadRequest {
+			return resp.StatusCode, nil
+		}
+		text = apiResponse.Error
+	} else if text == ok && resp.StatusCode < http.StatusBadRequest {
+		return resp.StatusCode, nil
 	}
-	return nil
+	if text == "" {
+		return 0, newResponseError(resp, buf)
+	}
+	if resp.StatusCode < http.StatusBadRequest {
+		return 0, &SlackError{Code: text}
+	}
+	providerErr := newStatusError(resp.StatusCode, nil)
+	providerErr.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+	providerErr.Err = &SlackError{Code: text}
+	return 0, providerErr
 }
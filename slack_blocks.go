@@ -0,0 +1,126 @@
+package notify
+
+import "context"
+
+// Block kit text object types
+const (
+	SlackMarkdown  = "mrkdwn"
+	SlackPlainText = "plain_text"
+)
+
+// slackMaxSectionFields is the number of fields a section can hold
+const slackMaxSectionFields = 10
+
+// SlackText is a block kit text object
+type SlackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// SlackMarkdownText returns a mrkdwn text object
+func SlackMarkdownText(text string) SlackText {
+	return SlackText{Type: SlackMarkdown, Text: text}
+}
+
+// SlackSection returns a section block with markdown text and optional
+// fields rendered in two columns
+func SlackSection(text string, fields ...string) SlackBlock {
+	block := SlackBlock{"type": "section"}
+	if text != "" {
+		block["text"] = SlackMarkdownText(text)
+	}
+	if len(fields) > 0 {
+		if len(fields) > slackMaxSectionFields {
+			fields = fields[:slackMaxSectionFields]
+		}
+		texts := make([]SlackText, 0, len(fields))
+		for _, field := range fields {
+			texts = append(texts, SlackMarkdownText(field))
+		}
+		block["fields"] = texts
+	}
+	return block
+}
+
+// SlackFields returns a section block of the fields only
+func SlackFields(fields ...string) SlackBlock {
+	return SlackSection("", fields...)
+}
+
+// SlackHeader returns a header block, rendered as large bold plain text
+func SlackHeader(text string) SlackBlock {
+	return SlackBlock{"type": "header", "text": SlackText{Type: SlackPlainText, Text: text}}
+}
+
+// SlackDivider returns a divider block
+func SlackDivider() SlackBlock {
+	return SlackBlock{"type": "divider"}
+}
+
+// SlackContext returns a context block of small markdown texts
+func SlackContext(elements ...string) SlackBlock {
+	texts := make([]SlackText, 0, len(elements))
+	for _, element := range elements {
+		texts = append(texts, SlackMarkdownText(element))
+	}
+	return SlackBlock{"type": "context", "elements": texts}
+}
+
+// SendBlocks posts a block kit message, text is shown in notifications
+// and by clients not rendering blocks
+func (sc *SlackClient) SendBlocks(text string, blocks ...SlackBlock) error {
+	return sc.sendHTTPRequest(context.Background(), &SlackMessage{
+		Text:     text,
+		Username: sc.UserName,
+		IconURL:  sc.IconURL,
+		Channel:  sc.Channel,
+		Blocks:   blocks,
+	})
+}
+
+// Button styles, the default one is neutral
+const (
+	SlackButtonPrimary = "primary"
+	SlackButtonDanger  = "danger"
+)
+
+// SlackOption is an option of select menus
+type SlackOption struct {
+	Text  SlackText `json:"text"`
+	Value string    `json:"value"`
+}
+
+// SlackSelectOption returns an option of a select menu
+func SlackSelectOption(label, value string) SlackOption {
+	return SlackOption{Text: SlackText{Type: SlackPlainText, Text: label}, Value: value}
+}
+
+// SlackButton returns a button element, clicks are dispatched by SlackHandler
+// with the action id and value
+func SlackButton(label, actionID, value, style string) SlackBlock {
+	button := SlackBlock{
+		"type":      "button",
+		"text":      SlackText{Type: SlackPlainText, Text: label},
+		"action_id": actionID,
+		"value":     value,
+	}
+	if style != "" {
+		button["style"] = style
+	}
+	return button
+}
+
+// SlackSelect returns a static select menu element
+func SlackSelect(placeholder, actionID string, options ...SlackOption) SlackBlock {
+	return SlackBlock{
+		"type":        "static_select",
+		"placeholder": SlackText{Type: SlackPlainText, Text: placeholder},
+		"action_id":   actionID,
+		"options":     options,
+	}
+}
+
+// SlackActions returns an actions block of interactive elements
+func SlackActions(elements ...SlackBlock) SlackBlock {
+	return SlackBlock{"type": "actions", "elements": elements}
+}
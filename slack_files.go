@@ -0,0 +1,70 @@
+package notify
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// SlackFile is an uploaded file
+type SlackFile struct {
+	ID    string `json:"id"`
+	Title string `json:"title,omitempty"`
+}
+
+// SendFile uploads the file to the channel with an optional initial comment,
+// for results too large for a message (bot token only). The file is sent to
+// the upload url returned by files.getUploadURLExternal then shared with
+// files.completeUploadExternal.
+func (sc *SlackClient) SendFile(filename string, data []byte, comment string) error {
+	if sc.mode() != SlackModeToken {
+		return ErrSlackTokenRequired
+	}
+	var upload struct {
+		UploadURL string `json:"upload_url"`
+		FileID    string `json:"file_id"`
+	}
+	values := url.Values{"filename": {filename}, "length": {strconv.Itoa(len(data))}}
+	if err := sc.callAPIForm(context.Background(), "files.getUploadURLExternal", values, &upload); err != nil {
+		return err
+	}
+
+	req, err := retryablehttp.NewRequest(http.MethodPost, upload.UploadURL, data)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/octet-stream")
+	resp, err := doTimeout(sc.client, req, sc.TimeOut)
+	if err != nil {
+		return err
+	}
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck // silent fail
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return newResponseError(resp, buf)
+	}
+
+	complete := map[string]interface{}{
+		"files":      []SlackFile{{ID: upload.FileID, Title: filename}},
+		"channel_id": sc.Channel,
+	}
+	if comment != "" {
+		complete["initial_comment"] = comment
+	}
+	if sc.ThreadTS != "" {
+		complete["thread_ts"] = sc.ThreadTS
+	}
+	ctx := context.Background()
+	if err := sc.waitChannel(ctx, sc.Channel); err != nil {
+		return err
+	}
+	return sc.callAPI(ctx, "files.completeUploadExternal", complete, nil)
+}
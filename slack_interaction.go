@@ -0,0 +1,174 @@
+package notify
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// slackSignatureMaxAge rejects requests older than this to prevent replays
+const slackSignatureMaxAge = 5 * time.Minute
+
+// maxSlackRequestSize bounds the body of interaction requests
+const maxSlackRequestSize = 1 << 20
+
+// ErrInvalidSlackSignature is returned for requests not signed by slack
+var ErrInvalidSlackSignature = errors.New("invalid slack signature")
+
+// SlackCommand is a slash command invocation
+type SlackCommand struct {
+	TeamID      string
+	ChannelID   string
+	ChannelName string
+	UserID      string
+	UserName    string
+	Command     string
+	Text        string
+	ResponseURL string
+	TriggerID   string
+}
+
+// SlackInteraction is the payload of interactive components and shortcuts
+type SlackInteraction struct {
+	Type       string `json:"type"`
+	CallbackID string `json:"callback_id"`
+	TriggerID  string `json:"trigger_id"`
+	User       struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+		Name     string `json:"name"`
+	} `json:"user"`
+	Channel struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"channel"`
+	Message struct {
+		TS   string `json:"ts"`
+		Text string `json:"text"`
+	} `json:"message"`
+	Actions     []SlackInteractionAction `json:"actions"`
+	ResponseURL string                   `json:"response_url"`
+}
+
+// SlackInteractionAction is an action triggered by the user
+type SlackInteractionAction struct {
+	ActionID string `json:"action_id"`
+	BlockID  string `json:"block_id"`
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Type     string `json:"type"`
+	// SelectedOption is the choice of select menus
+	SelectedOption *SlackOption `json:"selected_option,omitempty"`
+}
+
+// SlackActionHandler handles an action of an interaction, the returned
+// message replaces the original one
+type SlackActionHandler func(interaction *SlackInteraction, action *SlackInteractionAction) *SlackMessage
+
+// SlackHandler receives slash commands and interactions from slack
+type SlackHandler struct {
+	SigningSecret string
+	// OnCommand is called for slash commands, the returned message is the reply
+	OnCommand func(command *SlackCommand) *SlackMessage
+	// OnInteraction is called for interactive components, the returned message replaces the original one
+	OnInteraction func(interaction *SlackInteraction) *SlackMessage
+	// Actions are called for the actions of interactions by action id,
+	// before OnInteraction
+	Actions map[string]SlackActionHandler
+}
+
+// VerifySlackSignature checks the request was signed with the signing secret
+func VerifySlackSignature(signingSecret string, header http.Header, body []byte) error {
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSlackSignature
+	}
+	if math.Abs(time.Since(time.Unix(seconds, 0)).Seconds()) > slackSignatureMaxAge.Seconds() {
+		return ErrInvalidSlackSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(signingSecret))
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(header.Get("X-Slack-Signature"))) {
+		return ErrInvalidSlackSignature
+	}
+	return nil
+}
+
+// ServeHTTP validates and dispatches the request to the callbacks
+func (h *SlackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxSlackRequestSize))
+	if err != nil {
+		http.Error(w, "could not read request", http.StatusBadRequest)
+		return
+	}
+	if err := VerifySlackSignature(h.SigningSecret, r.Header, body); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+
+	var reply *SlackMessage
+	if payload := values.Get("payload"); payload != "" {
+		var interaction SlackInteraction
+		if err := json.Unmarshal([]byte(payload), &interaction); err != nil {
+			http.Error(w, "invalid payload", http.StatusBadRequest)
+			return
+		}
+		for i := range interaction.Actions {
+			action := &interaction.Actions[i]
+			if handler, ok := h.Actions[action.ActionID]; ok {
+				if message := handler(&interaction, action); message != nil && reply == nil {
+					reply = message
+				}
+			}
+		}
+		if h.OnInteraction != nil {
+			if message := h.OnInteraction(&interaction); message != nil && reply == nil {
+				reply = message
+			}
+		}
+	} else {
+		command := &SlackCommand{
+			TeamID:      values.Get("team_id"),
+			ChannelID:   values.Get("channel_id"),
+			ChannelName: values.Get("channel_name"),
+			UserID:      values.Get("user_id"),
+			UserName:    values.Get("user_name"),
+			Command:     values.Get("command"),
+			Text:        values.Get("text"),
+			ResponseURL: values.Get("response_url"),
+			TriggerID:   values.Get("trigger_id"),
+		}
+		if h.OnCommand != nil {
+			reply = h.OnCommand(command)
+		}
+	}
+
+	if reply == nil {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	//nolint:errcheck // client went away
+	json.NewEncoder(w).Encode(reply)
+}
@@ -0,0 +1,124 @@
+package notify
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// DefaultSNSTimeout to conclude operations
+const DefaultSNSTimeout = 5 * time.Second
+
+// SNSSeverityAttribute is the message attribute carrying the severity
+const SNSSeverityAttribute = "severity"
+
+// SNSClient publishes notifications to an aws sns topic
+type SNSClient struct {
+	client   *retryablehttp.Client
+	TopicARN string
+	// Region defaults to the one of the topic arn
+	Region string
+	// Credentials fall back to the default aws chain when empty
+	Credentials AWSCredentials
+	// Subject of email subscriptions
+	Subject string
+	TimeOut time.Duration
+}
+
+// SendInfo to sns
+func (sc *SNSClient) SendInfo(message string) error {
+	return sc.SendInfoCtx(context.Background(), message)
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (sc *SNSClient) SendInfoCtx(ctx context.Context, message string) error {
+	return sc.PublishCtx(ctx, message, map[string]string{SNSSeverityAttribute: "info"})
+}
+
+// SendWarning to sns
+func (sc *SNSClient) SendWarning(message string) error {
+	return sc.PublishCtx(context.Background(), message, map[string]string{SNSSeverityAttribute: "warning"})
+}
+
+// SendError to sns
+func (sc *SNSClient) SendError(message string) error {
+	return sc.PublishCtx(context.Background(), message, map[string]string{SNSSeverityAttribute: "error"})
+}
+
+// Publish the message to the topic with string message attributes
+func (sc *SNSClient) Publish(message string, attributes map[string]string) error {
+	return sc.PublishCtx(context.Background(), message, attributes)
+}
+
+// PublishCtx is Publish canceled with the context
+func (sc *SNSClient) PublishCtx(ctx context.Context, message string, attributes map[string]string) error {
+	values := url.Values{
+		"Action":   {"Publish"},
+		"Version":  {"2010-03-31"},
+		"TopicArn": {sc.TopicARN},
+		"Message":  {message},
+	}
+	if sc.Subject != "" {
+		values.Set("Subject", sc.Subject)
+	}
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		prefix := "MessageAttributes.entry." + strconv.Itoa(i+1) + "."
+		values.Set(prefix+"Name", name)
+		values.Set(prefix+"Value.DataType", "String")
+		values.Set(prefix+"Value.StringValue", attributes[name])
+	}
+
+	region := sc.region()
+	body := []byte(values.Encode())
+	req, err := retryablehttp.NewRequest(http.MethodPost, "https://sns."+region+".amazonaws.com/", body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	credentials, err := sc.Credentials.resolve()
+	if err != nil {
+		return err
+	}
+	signAWSRequest(req.Request, body, "sns", region, credentials, time.Now())
+
+	resp, err := doTimeout(sc.client, req.WithContext(ctx), sc.TimeOut)
+	if err != nil {
+		return err
+	}
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck // silent fail
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newResponseError(resp, buf)
+	}
+	return nil
+}
+
+// region returns the configured region, the one of the topic or AWS_REGION
+func (sc *SNSClient) region() string {
+	if sc.Region != "" {
+		return sc.Region
+	}
+	// arn:aws:sns:region:account:topic
+	if parts := strings.Split(sc.TopicARN, ":"); len(parts) == 6 && parts[3] != "" {
+		return parts[3]
+	}
+	return os.Getenv("AWS_REGION")
+}
@@ -0,0 +1,152 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// DefaultSplunkTimeout to conclude operations
+const DefaultSplunkTimeout = 10 * time.Second
+
+// SplunkClient posts notifications to a Splunk HTTP Event Collector
+type SplunkClient struct {
+	client     *retryablehttp.Client
+	URL        string
+	Token      string
+	SourceType string
+	Index      string
+	Source     string
+	// Host of the events, defaults to the hostname of the machine
+	Host string
+	// BatchSize posts that many events in a single request
+	BatchSize int
+	TimeOut   time.Duration
+
+	batch recordBatch
+}
+
+// SplunkEvent json structure of the event endpoint
+type SplunkEvent struct {
+	Time       float64     `json:"time,omitempty"`
+	Host       string      `json:"host,omitempty"`
+	Source     string      `json:"source,omitempty"`
+	SourceType string      `json:"sourcetype,omitempty"`
+	Index      string      `json:"index,omitempty"`
+	Event      interface{} `json:"event"`
+}
+
+// SplunkEventData is the structured event of a notification
+type SplunkEventData struct {
+	Message  string `json:"message"`
+	Severity string `json:"severity"`
+}
+
+// SplunkResponse structure
+type SplunkResponse struct {
+	Text string `json:"text"`
+	Code int    `json:"code"`
+}
+
+// SendInfo to splunk, with batching enabled events are posted once the batch is full
+func (sc *SplunkClient) SendInfo(message string) error {
+	return sc.SendInfoCtx(context.Background(), message)
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (sc *SplunkClient) SendInfoCtx(ctx context.Context, message string) error {
+	return sc.send(ctx, "info", message)
+}
+
+// SendWarning to splunk
+func (sc *SplunkClient) SendWarning(message string) error {
+	return sc.send(context.Background(), "warning", message)
+}
+
+// SendError to splunk
+func (sc *SplunkClient) SendError(message string) error {
+	return sc.send(context.Background(), "error", message)
+}
+
+func (sc *SplunkClient) send(ctx context.Context, severity, message string) error {
+	if records := sc.batch.add(newSeverityRecord(severity, message), sc.BatchSize); len(records) > 0 {
+		return sc.sendEvents(ctx, records)
+	}
+	return nil
+}
+
+// Flush posts the pending batched events
+func (sc *SplunkClient) Flush() error {
+	if records := sc.batch.drain(); len(records) > 0 {
+		return sc.sendEvents(context.Background(), records)
+	}
+	return nil
+}
+
+func (sc *SplunkClient) sendEvents(ctx context.Context, records []record) error {
+	// the collector accepts several events concatenated in the same body
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	host := sc.Host
+	if host == "" {
+		host, _ = os.Hostname()
+	}
+	for _, r := range records {
+		event := SplunkEvent{
+			Time:       float64(r.Time.UnixNano()) / float64(time.Second),
+			Host:       host,
+			Source:     sc.Source,
+			SourceType: sc.SourceType,
+			Index:      sc.Index,
+			Event:      SplunkEventData{Message: r.Message, Severity: r.Severity},
+		}
+		if err := enc.Encode(event); err != nil {
+			return err
+		}
+	}
+	return sc.sendHTTPRequest(ctx, body.Bytes())
+}
+
+func (sc *SplunkClient) sendHTTPRequest(ctx context.Context, body []byte) error {
+	URL := strings.TrimSuffix(sc.URL, "/")
+	if !strings.HasSuffix(URL, "/services/collector/event") {
+		URL += "/services/collector/event"
+	}
+	req, err := retryablehttp.NewRequest(http.MethodPost, URL, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Authorization", "Splunk "+sc.Token)
+
+	resp, err := doTimeout(sc.client, req.WithContext(ctx), sc.TimeOut)
+	if err != nil {
+		return err
+	}
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck // silent fail
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return newResponseError(resp, buf)
+	}
+	var splunkResponse SplunkResponse
+	if err := json.Unmarshal(buf, &splunkResponse); err != nil {
+		return err
+	}
+	if splunkResponse.Code != 0 {
+		return fmt.Errorf("splunk: %s (code %d)", splunkResponse.Text, splunkResponse.Code)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package notify
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ProviderStats holds the delivery counters of a single provider
+type ProviderStats struct {
+	Sent        uint64    `json:"sent"`
+	Failed      uint64    `json:"failed"`
+	Retried     uint64    `json:"retried"`
+	Dropped     uint64    `json:"dropped"`
+	LastError   string    `json:"last_error,omitempty"`
+	LastErrorAt time.Time `json:"last_error_at"`
+	LastSuccess time.Time `json:"last_success"`
+}
+
+// Stats is a point in time snapshot of the notification engine
+type Stats struct {
+	// QueueDepth is the number of messages waiting for async delivery
+	QueueDepth int `json:"queue_depth"`
+	// QueueBytes is the size of the messages waiting for async delivery
+	QueueBytes int64 `json:"queue_bytes"`
+	// QueueWorkers is the number of goroutines delivering queued messages
+	QueueWorkers int `json:"queue_workers"`
+	// QueueCorruptRecords is the number of unreadable lines skipped replaying the persisted queue
+	QueueCorruptRecords int `json:"queue_corrupt_records,omitempty"`
+	// Providers contains the counters keyed by provider name
+	Providers map[string]ProviderStats `json:"providers"`
+}
+
+// maxRecentErrors is the number of errors kept for troubleshooting
+const maxRecentErrors = 20
+
+// RecentError is a failed delivery kept for troubleshooting
+type RecentError struct {
+	Provider string    `json:"provider"`
+	Error    string    `json:"error"`
+	Time     time.Time `json:"time"`
+}
+
+// statsCollector records delivery outcomes per provider
+type statsCollector struct {
+	sync.RWMutex
+	providers map[string]*ProviderStats
+	errors    []RecentError
+}
+
+func newStatsCollector() *statsCollector {
+	return &statsCollector{providers: make(map[string]*ProviderStats)}
+}
+
+// get returns the counters of a provider, the caller must hold the lock
+func (s *statsCollector) get(provider string) *ProviderStats {
+	stats, ok := s.providers[provider]
+	if !ok {
+		stats = &ProviderStats{}
+		s.providers[provider] = stats
+	}
+	return stats
+}
+
+func (s *statsCollector) record(provider string, err error) {
+	s.Lock()
+	defer s.Unlock()
+
+	stats := s.get(provider)
+	if err != nil {
+		stats.Failed++
+		stats.LastError = err.Error()
+		stats.LastErrorAt = time.Now()
+		s.errors = append(s.errors, RecentError{Provider: provider, Error: stats.LastError, Time: stats.LastErrorAt})
+		if len(s.errors) > maxRecentErrors {
+			s.errors = s.errors[len(s.errors)-maxRecentErrors:]
+		}
+		return
+	}
+	stats.Sent++
+	stats.LastSuccess = time.Now()
+}
+
+func (s *statsCollector) retried(provider string) {
+	s.Lock()
+	s.get(provider).Retried++
+	s.Unlock()
+}
+
+func (s *statsCollector) dropped(provider string) {
+	s.Lock()
+	s.get(provider).Dropped++
+	s.Unlock()
+}
+
+func (s *statsCollector) snapshot() map[string]ProviderStats {
+	s.RLock()
+	defer s.RUnlock()
+
+	providers := make(map[string]ProviderStats, len(s.providers))
+	for name, stats := range s.providers {
+		providers[name] = *stats
+	}
+	return providers
+}
+
+// recentErrors returns the last failed deliveries, newest last
+func (s *statsCollector) recentErrors() []RecentError {
+	s.RLock()
+	defer s.RUnlock()
+
+	return append([]RecentError(nil), s.errors...)
+}
+
+// Stats returns a snapshot of the delivery counters and queue depth
+func (n *Notify) Stats() Stats {
+	stats := Stats{
+		QueueDepth:   len(n.queue.messages),
+		QueueBytes:   atomic.LoadInt64(&n.queue.bytes),
+		QueueWorkers: n.queue.workers,
+		Providers:    n.stats.snapshot(),
+	}
+	if n.queue.wal != nil {
+		stats.QueueCorruptRecords = n.queue.wal.corruptRecords()
+	}
+	return stats
+}
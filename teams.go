@@ -0,0 +1,274 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// DefaultTeamsTimeout to conclude operations
+const DefaultTeamsTimeout = 5 * time.Second
+
+// Teams mention types
+const (
+	TeamsMentionUser = "person"
+	TeamsMentionTag  = "tag"
+)
+
+// TeamsCardFormat is the payload format posted to the webhook
+type TeamsCardFormat string
+
+// Teams card formats
+const (
+	// TeamsAdaptiveCard is the default format supporting mentions
+	TeamsAdaptiveCard TeamsCardFormat = "adaptive"
+	// TeamsMessageCard is the legacy office 365 connector card
+	TeamsMessageCard TeamsCardFormat = "messagecard"
+)
+
+// Theme colors of the severity helpers, matching the slack attachment ones.
+// Adaptive cards have no theme color, their container style is used instead.
+const (
+	TeamsColorGood    = "2EB886"
+	TeamsColorWarning = "DAA038"
+	TeamsColorDanger  = "A30200"
+)
+
+// TeamsClient handling microsoft teams incoming webhooks
+type TeamsClient struct {
+	client     *retryablehttp.Client
+	WebHookURL string
+	// Mentions are notified with every adaptive card
+	Mentions   []TeamsMention
+	CardFormat TeamsCardFormat
+	// Severities overrides the colors and labels of the severity helpers
+	Severities SeverityScheme
+	TimeOut    time.Duration
+}
+
+// TeamsMention is a user (by aad object id or upn) or tag mentioned in a card
+type TeamsMention struct {
+	ID   string
+	Name string
+	// Type is TeamsMentionUser (default) or TeamsMentionTag
+	Type string
+}
+
+// TeamsMessage json structure
+type TeamsMessage struct {
+	Type        string            `json:"type"`
+	Attachments []TeamsAttachment `json:"attachments"`
+}
+
+// TeamsAttachment wraps a card in a message
+type TeamsAttachment struct {
+	ContentType string        `json:"contentType"`
+	Content     *AdaptiveCard `json:"content"`
+}
+
+// AdaptiveCard json structure
+type AdaptiveCard struct {
+	Schema  string                   `json:"$schema"`
+	Type    string                   `json:"type"`
+	Version string                   `json:"version"`
+	Body    []map[string]interface{} `json:"body"`
+	MSTeams *AdaptiveCardMSTeams     `json:"msteams,omitempty"`
+}
+
+// AdaptiveCardMSTeams holds the teams specific card properties
+type AdaptiveCardMSTeams struct {
+	Width    string               `json:"width,omitempty"`
+	Entities []AdaptiveCardEntity `json:"entities,omitempty"`
+}
+
+// AdaptiveCardEntity is a mention entity of a card
+type AdaptiveCardEntity struct {
+	Type      string                 `json:"type"`
+	Text      string                 `json:"text"`
+	Mentioned map[string]interface{} `json:"mentioned"`
+}
+
+// MessageCard json structure of the legacy connector card
+type MessageCard struct {
+	Type       string               `json:"@type"`
+	Context    string               `json:"@context"`
+	ThemeColor string               `json:"themeColor,omitempty"`
+	Summary    string               `json:"summary"`
+	Title      string               `json:"title,omitempty"`
+	Text       string               `json:"text,omitempty"`
+	Sections   []MessageCardSection `json:"sections,omitempty"`
+}
+
+// MessageCardSection of a message card
+type MessageCardSection struct {
+	ActivityTitle string            `json:"activityTitle,omitempty"`
+	Text          string            `json:"text,omitempty"`
+	Facts         []MessageCardFact `json:"facts,omitempty"`
+}
+
+// MessageCardFact is a name value pair of a section
+type MessageCardFact struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// NewMessageCard returns a message card rendering the text with the theme color
+func NewMessageCard(text, themeColor string) *MessageCard {
+	summary := text
+	if runes := []rune(summary); len(runes) > 80 {
+		summary = string(runes[:80])
+	}
+	return &MessageCard{
+		Type:       "MessageCard",
+		Context:    "https://schema.org/extensions",
+		ThemeColor: themeColor,
+		Summary:    summary,
+		Text:       text,
+	}
+}
+
+// NewAdaptiveCard returns a card rendering the text and notifying the mentions,
+// they are referenced in the text as <at>Name</at> or appended when missing
+func NewAdaptiveCard(text string, mentions []TeamsMention) *AdaptiveCard {
+	card := &AdaptiveCard{
+		Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+		Type:    "AdaptiveCard",
+		Version: "1.2",
+	}
+	var missing []string
+	for _, mention := range mentions {
+		tag := "<at>" + mention.Name + "</at>"
+		if !strings.Contains(text, tag) {
+			missing = append(missing, tag)
+		}
+		mentioned := map[string]interface{}{"id": mention.ID, "name": mention.Name}
+		if mention.Type != "" && mention.Type != TeamsMentionUser {
+			mentioned["type"] = mention.Type
+		}
+		if card.MSTeams == nil {
+			card.MSTeams = &AdaptiveCardMSTeams{}
+		}
+		card.MSTeams.Entities = append(card.MSTeams.Entities, AdaptiveCardEntity{Type: "mention", Text: tag, Mentioned: mentioned})
+	}
+	if len(missing) > 0 {
+		text = strings.Join(missing, " ") + " " + text
+	}
+	card.Body = []map[string]interface{}{{"type": "TextBlock", "text": text, "wrap": true}}
+	return card
+}
+
+// SendInfo to teams
+func (tc *TeamsClient) SendInfo(message string) error {
+	_, err := tc.send(context.Background(), message, SeverityInfo)
+	return err
+}
+
+// SendWarning to teams
+func (tc *TeamsClient) SendWarning(message string) error {
+	_, err := tc.send(context.Background(), message, SeverityWarning)
+	return err
+}
+
+// SendError to teams
+func (tc *TeamsClient) SendError(message string) error {
+	_, err := tc.send(context.Background(), message, SeverityError)
+	return err
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (tc *TeamsClient) SendInfoCtx(ctx context.Context, message string) error {
+	_, err := tc.send(ctx, message, SeverityInfo)
+	return err
+}
+
+// SendWarningCtx is SendWarning canceled with the context
+func (tc *TeamsClient) SendWarningCtx(ctx context.Context, message string) error {
+	_, err := tc.send(ctx, message, SeverityWarning)
+	return err
+}
+
+// SendErrorCtx is SendError canceled with the context
+func (tc *TeamsClient) SendErrorCtx(ctx context.Context, message string) error {
+	_, err := tc.send(ctx, message, SeverityError)
+	return err
+}
+
+// SendInfoResult sends the info message and describes its delivery
+func (tc *TeamsClient) SendInfoResult(ctx context.Context, message string) (*DeliveryResult, error) {
+	return tc.send(ctx, message, SeverityInfo)
+}
+
+// teamsContainerStyles are the adaptive card container styles of the severities
+var teamsContainerStyles = map[string]string{SeverityInfo: "good", SeverityWarning: "warning", SeverityError: "attention"}
+
+// send the message in the configured format
+func (tc *TeamsClient) send(ctx context.Context, message, severity string) (*DeliveryResult, error) {
+	style := tc.Severities.Style(severity)
+	message = style.labeled(message)
+	if tc.CardFormat == TeamsMessageCard {
+		return tc.post(ctx, NewMessageCard(message, style.hexColor()))
+	}
+	card := NewAdaptiveCard(message, tc.Mentions)
+	card.Body = []map[string]interface{}{{"type": "Container", "style": teamsContainerStyles[severity], "bleed": true, "items": card.Body}}
+	return tc.sendCard(ctx, card)
+}
+
+// SendCard posts an adaptive card
+func (tc *TeamsClient) SendCard(card *AdaptiveCard) error {
+	_, err := tc.sendCard(context.Background(), card)
+	return err
+}
+
+func (tc *TeamsClient) sendCard(ctx context.Context, card *AdaptiveCard) (*DeliveryResult, error) {
+	return tc.post(ctx, &TeamsMessage{
+		Type: "message",
+		Attachments: []TeamsAttachment{{
+			ContentType: "application/vnd.microsoft.card.adaptive",
+			Content:     card,
+		}},
+	})
+}
+
+// SendTeamsNotification with json structure
+func (tc *TeamsClient) SendTeamsNotification(teamsMessage interface{}) error {
+	return tc.SendTeamsNotificationCtx(context.Background(), teamsMessage)
+}
+
+// SendTeamsNotificationCtx is SendTeamsNotification canceled with the context
+func (tc *TeamsClient) SendTeamsNotificationCtx(ctx context.Context, teamsMessage interface{}) error {
+	_, err := tc.post(ctx, teamsMessage)
+	return err
+}
+
+func (tc *TeamsClient) post(ctx context.Context, teamsMessage interface{}) (*DeliveryResult, error) {
+	body, err := json.Marshal(teamsMessage)
+	if err != nil {
+		return nil, err
+	}
+	req, err := retryablehttp.NewRequest(http.MethodPost, tc.WebHookURL, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Content-Type", "application/json")
+
+	resp, err := doTimeout(tc.client, req.WithContext(ctx), tc.TimeOut)
+	if err != nil {
+		return nil, err
+	}
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	//nolint:errcheck // silent fail
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, newResponseError(resp, buf)
+	}
+	return newDeliveryResult(ProviderTeams, resp.StatusCode), nil
+}
@@ -1,11 +1,15 @@
 package notify
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/projectdiscovery/retryablehttp-go"
@@ -14,38 +18,153 @@ import (
 // DefaultTelegraTimeout to conclude operations
 const (
 	DefaultTelegraTimeout = 5 * time.Second
-	Endpoint              = "https://api.telegram.org/bot{{apikey}}/sendMessage?chat_id={{chatid}}&text={{message}}"
+	// Deprecated: messages are sent with a form post to TelegramAPIEndpoint
+	Endpoint        = "https://api.telegram.org/bot{{apikey}}/sendMessage?chat_id={{chatid}}&text={{message}}"
+	GetChatEndpoint = "https://api.telegram.org/bot{{apikey}}/getChat?chat_id={{chatid}}"
+	// EditMarkupEndpoint is used to probe the existence of a sent message
+	EditMarkupEndpoint = "https://api.telegram.org/bot{{apikey}}/editMessageReplyMarkup?chat_id={{chatid}}&message_id={{messageid}}"
 )
 
+// TelegramMaxMessageLength is the longest text accepted by sendMessage
+const TelegramMaxMessageLength = 4096
+
+// TelegramParseMode is the formatting of the message text
+type TelegramParseMode string
+
+// Telegram parse modes
+const (
+	TelegramParseModeNone       TelegramParseMode = ""
+	TelegramParseModeMarkdownV2 TelegramParseMode = "MarkdownV2"
+	TelegramParseModeHTML       TelegramParseMode = "HTML"
+)
+
+// telegramMarkdownV2Escaper escapes the characters reserved by MarkdownV2
+var telegramMarkdownV2Escaper = strings.NewReplacer(
+	"_", "\\_", "*", "\\*", "[", "\\[", "]", "\\]", "(", "\\(", ")", "\\)", "~", "\\~", "`", "\\`",
+	">", "\\>", "#", "\\#", "+", "\\+", "-", "\\-", "=", "\\=", "|", "\\|", "{", "\\{", "}", "\\}",
+	".", "\\.", "!", "\\!", "\\", "\\\\",
+)
+
+// EscapeMarkdownV2 escapes text to be sent literally with the MarkdownV2 parse mode
+func EscapeMarkdownV2(text string) string {
+	return telegramMarkdownV2Escaper.Replace(text)
+}
+
 // TelegramClient handling webhooks
 type TelegramClient struct {
-	client  *retryablehttp.Client
-	apiKEY  string
-	chatID  string
-	TimeOut time.Duration
+	client *retryablehttp.Client
+	apiKEY string
+	chatID string
+	// ParseMode formats the messages fitting a single chunk, longer ones are
+	// sent as plain text since a cut can break the escapes, tags and entities
+	ParseMode TelegramParseMode
+	TimeOut   time.Duration
+
+	// chatIDs caches the resolved @usernames
+	chatIDsMutex sync.RWMutex
+	chatIDs      map[string]string
 }
 
 // SendInfo to telegram
 func (dc *TelegramClient) SendInfo(message string) (err error) {
-	return dc.sendHTTPRequest(message)
+	return dc.SendInfoCtx(context.Background(), message)
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (dc *TelegramClient) SendInfoCtx(ctx context.Context, message string) error {
+	_, err := dc.sendHTTPRequest(ctx, message)
+	return err
+}
+
+// SendInfoResult sends the message and describes the delivery of its first chunk
+func (dc *TelegramClient) SendInfoResult(ctx context.Context, message string) (*DeliveryResult, error) {
+	return dc.sendHTTPRequest(ctx, message)
+}
+
+// SendConfirmed delivers the message and checks it exists afterwards.
+// The bot api can't fetch messages, so existence is probed with a no-op
+// edit of the reply markup which fails only for missing messages.
+func (dc *TelegramClient) SendConfirmed(message string) (*DeliveryResult, error) {
+	return dc.SendConfirmedCtx(context.Background(), message)
 }
 
-func (dc *TelegramClient) sendHTTPRequest(message string) error {
+// SendConfirmedCtx is SendConfirmed canceled with the context
+func (dc *TelegramClient) SendConfirmedCtx(ctx context.Context, message string) (*DeliveryResult, error) {
+	result, err := dc.sendHTTPRequest(ctx, message)
+	if err != nil {
+		return nil, err
+	}
 	r := strings.NewReplacer(
 		"{{apikey}}", dc.apiKEY,
-		"{{chatid}}", dc.chatID,
-		"{{message}}", message,
+		"{{chatid}}", url.QueryEscape(result.Channel),
+		"{{messageid}}", result.MessageID,
 	)
-	URL := r.Replace(Endpoint)
+	result.Confirmed, err = poll(ctx, func() (bool, error) {
+		var tgresponse TelegramResponse
+		if err := dc.get(ctx, r.Replace(EditMarkupEndpoint), &tgresponse); err != nil {
+			return false, err
+		}
+		return tgresponse.Ok || strings.Contains(tgresponse.Description, "message is not modified"), nil
+	})
+	return result, err
+}
+
+// ResolveChatID returns the numeric id of a @username channel or group,
+// other identifiers are returned as is. Resolved ids are cached.
+func (dc *TelegramClient) ResolveChatID(chatID string) (string, error) {
+	return dc.ResolveChatIDCtx(context.Background(), chatID)
+}
+
+// ResolveChatIDCtx is ResolveChatID canceled with the context
+func (dc *TelegramClient) ResolveChatIDCtx(ctx context.Context, chatID string) (string, error) {
+	if !strings.HasPrefix(chatID, "@") {
+		return chatID, nil
+	}
+
+	dc.chatIDsMutex.RLock()
+	resolved, ok := dc.chatIDs[chatID]
+	dc.chatIDsMutex.RUnlock()
+	if ok {
+		return resolved, nil
+	}
+
+	r := strings.NewReplacer(
+		"{{apikey}}", dc.apiKEY,
+		"{{chatid}}", url.QueryEscape(chatID),
+	)
+	var chatResponse struct {
+		TelegramResponse
+		Result struct {
+			ID int64 `json:"id"`
+		} `json:"result"`
+	}
+	if err := dc.get(ctx, r.Replace(GetChatEndpoint), &chatResponse); err != nil {
+		return "", err
+	}
+	if !chatResponse.Ok {
+		return "", fmt.Errorf("could not resolve %s: %s", chatID, chatResponse.Description)
+	}
+	resolved = strconv.FormatInt(chatResponse.Result.ID, 10)
+
+	dc.chatIDsMutex.Lock()
+	if dc.chatIDs == nil {
+		dc.chatIDs = make(map[string]string)
+	}
+	dc.chatIDs[chatID] = resolved
+	dc.chatIDsMutex.Unlock()
+
+	return resolved, nil
+}
+
+func (dc *TelegramClient) get(ctx context.Context, URL string, v interface{}) error {
 	req, err := retryablehttp.NewRequest(http.MethodGet, URL, nil)
 	if err != nil {
 		return err
 	}
-	resp, err := dc.client.Do(req)
+	resp, err := doTimeout(dc.client, req.WithContext(ctx), dc.TimeOut)
 	if err != nil {
 		return err
 	}
-
 	buf, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return err
@@ -53,18 +172,78 @@ func (dc *TelegramClient) sendHTTPRequest(message string) error {
 	//nolint:errcheck // silent fail
 	defer resp.Body.Close()
 
-	var tgresponse TelegramResponse
+	return json.Unmarshal(buf, v)
+}
+
+func (dc *TelegramClient) sendHTTPRequest(ctx context.Context, message string) (*DeliveryResult, error) {
+	chatID, err := dc.ResolveChatIDCtx(ctx, dc.chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	// messages over the limit are sent as consecutive numbered ones, the result is the first
+	chunks := numberedChunks(message, TelegramMaxMessageLength, chunkFormat)
+	parseMode := dc.ParseMode
+	if len(chunks) > 1 {
+		parseMode = TelegramParseModeNone
+	}
+	var result *DeliveryResult
+	for _, chunk := range chunks {
+		values := url.Values{"chat_id": {chatID}, "text": {chunk}}
+		if parseMode != TelegramParseModeNone {
+			values.Set("parse_mode", string(parseMode))
+		}
+		var sent struct {
+			MessageID int64 `json:"message_id"`
+		}
+		if err := dc.callAPI(ctx, "sendMessage", values, &sent); err != nil {
+			return nil, err
+		}
+		if result == nil {
+			result = newDeliveryResult(ProviderTelegram, http.StatusOK)
+			result.Channel = chatID
+			if sent.MessageID != 0 {
+				result.MessageID = strconv.FormatInt(sent.MessageID, 10)
+			}
+		}
+	}
+	return result, nil
+}
 
-	err = json.Unmarshal(buf, &tgresponse)
+// callAPI invokes a bot api method, the result is decoded in result when not nil
+func (dc *TelegramClient) callAPI(ctx context.Context, method string, values url.Values, result interface{}) error {
+	req, err := retryablehttp.NewRequest(http.MethodPost, TelegramAPIEndpoint+dc.apiKEY+"/"+method, []byte(values.Encode()))
 	if err != nil {
 		return err
 	}
+	req = req.WithContext(ctx)
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 
-	if !tgresponse.Ok {
-		return fmt.Errorf("%s", tgresponse.Description)
+	timeout := dc.TimeOut
+	if method == "getUpdates" && timeout > 0 {
+		// long polling holds the request open
+		timeout += telegramPollTimeout * time.Second
+	}
+	resp, err := doTimeout(dc.client, req, timeout)
+	if err != nil {
+		return err
 	}
+	//nolint:errcheck // silent fail
+	defer resp.Body.Close()
 
-	return nil
+	var tgresponse TelegramResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tgresponse); err != nil {
+		return err
+	}
+	if !tgresponse.Ok {
+		err := newStatusError(tgresponse.ErrorCode, []byte(tgresponse.Description))
+		err.RetryAfter = time.Duration(tgresponse.Parameters.RetryAfter) * time.Second
+		return err
+	}
+	if result == nil || len(tgresponse.Result) == 0 {
+		return nil
+	}
+	return json.Unmarshal(tgresponse.Result, result)
 }
 
 // TelegramResponse structure
@@ -72,4 +251,8 @@ type TelegramResponse struct {
 	Ok          bool   `json:"ok"`
 	ErrorCode   int    `json:"error_code,omitempty"`
 	Description string `json:"description,omitempty"`
+	Parameters  struct {
+		RetryAfter int `json:"retry_after,omitempty"`
+	} `json:"parameters,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
 }
@@ -0,0 +1,206 @@
+package notify
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// TelegramAPIEndpoint is the base url of the bot api methods
+	TelegramAPIEndpoint = "https://api.telegram.org/bot"
+	// telegramPollTimeout is the long polling duration of getUpdates, below the http timeout
+	telegramPollTimeout = 25
+	// telegramPollBackoff is the wait after a failed poll
+	telegramPollBackoff = 5 * time.Second
+)
+
+// TelegramCommand is a bot command received by the listener, eg. /mute 1h
+type TelegramCommand struct {
+	Name      string
+	Args      []string
+	ChatID    int64
+	MessageID int64
+	From      string
+}
+
+// TelegramCommandHandler handles a command, the returned text is sent back to the chat
+type TelegramCommandHandler func(command *TelegramCommand) string
+
+// TelegramCallbackHandler handles an inline keyboard press, the returned text is shown to the user
+type TelegramCallbackHandler func(callback *TelegramCallbackQuery) string
+
+// TelegramUpdate is an update returned by getUpdates
+type TelegramUpdate struct {
+	UpdateID      int64                  `json:"update_id"`
+	Message       *TelegramInputMessage  `json:"message,omitempty"`
+	CallbackQuery *TelegramCallbackQuery `json:"callback_query,omitempty"`
+}
+
+// TelegramCallbackQuery is sent when an inline keyboard button is pressed
+type TelegramCallbackQuery struct {
+	ID   string `json:"id"`
+	From struct {
+		ID       int64  `json:"id"`
+		Username string `json:"username"`
+	} `json:"from"`
+	Message *TelegramInputMessage `json:"message,omitempty"`
+	Data    string                `json:"data"`
+}
+
+// TelegramInputMessage is a message received by the bot
+type TelegramInputMessage struct {
+	MessageID int64 `json:"message_id"`
+	From      struct {
+		ID       int64  `json:"id"`
+		Username string `json:"username"`
+	} `json:"from"`
+	Chat struct {
+		ID int64 `json:"id"`
+	} `json:"chat"`
+	Text string `json:"text"`
+}
+
+// TelegramListener long polls the bot updates and dispatches commands
+type TelegramListener struct {
+	client *TelegramClient
+	// AllowedChats restricts the chats accepted, defaults to the client chat
+	AllowedChats []int64
+
+	mutex    sync.RWMutex
+	handlers map[string]TelegramCommandHandler
+	callback TelegramCallbackHandler
+	offset   int64
+}
+
+// NewListener returns a command listener using the bot of the client
+func (dc *TelegramClient) NewListener() *TelegramListener {
+	return &TelegramListener{client: dc, handlers: make(map[string]TelegramCommandHandler)}
+}
+
+// Handle registers the handler of a command name without the leading slash
+func (l *TelegramListener) Handle(command string, handler TelegramCommandHandler) {
+	l.mutex.Lock()
+	l.handlers[strings.TrimPrefix(command, "/")] = handler
+	l.mutex.Unlock()
+}
+
+// HandleCallback registers the handler of inline keyboard presses
+func (l *TelegramListener) HandleCallback(handler TelegramCallbackHandler) {
+	l.mutex.Lock()
+	l.callback = handler
+	l.mutex.Unlock()
+}
+
+// Listen polls the updates until the context is canceled
+func (l *TelegramListener) Listen(ctx context.Context) error {
+	allowed, err := l.allowedChats(ctx)
+	if err != nil {
+		return err
+	}
+	for {
+		updates, err := l.getUpdates(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(telegramPollBackoff):
+			}
+			continue
+		}
+		for _, update := range updates {
+			l.offset = update.UpdateID + 1
+			if query := update.CallbackQuery; query != nil && query.Message != nil && allowed[query.Message.Chat.ID] {
+				l.dispatchCallback(query)
+			}
+			if update.Message == nil || !allowed[update.Message.Chat.ID] {
+				continue
+			}
+			l.dispatch(update.Message)
+		}
+	}
+}
+
+func (l *TelegramListener) allowedChats(ctx context.Context) (map[int64]bool, error) {
+	allowed := make(map[int64]bool)
+	for _, chat := range l.AllowedChats {
+		allowed[chat] = true
+	}
+	if len(allowed) > 0 {
+		return allowed, nil
+	}
+	resolved, err := l.client.ResolveChatIDCtx(ctx, l.client.chatID)
+	if err != nil {
+		return nil, err
+	}
+	chat, err := strconv.ParseInt(resolved, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	allowed[chat] = true
+	return allowed, nil
+}
+
+// dispatch runs the handler of the command in the message
+func (l *TelegramListener) dispatch(message *TelegramInputMessage) {
+	if !strings.HasPrefix(message.Text, "/") {
+		return
+	}
+	fields := strings.Fields(message.Text)
+	// commands in groups may be addressed as /command@botname
+	name := strings.SplitN(strings.TrimPrefix(fields[0], "/"), "@", 2)[0]
+
+	l.mutex.RLock()
+	handler, ok := l.handlers[name]
+	l.mutex.RUnlock()
+	if !ok {
+		return
+	}
+	reply := handler(&TelegramCommand{
+		Name:      name,
+		Args:      fields[1:],
+		ChatID:    message.Chat.ID,
+		MessageID: message.MessageID,
+		From:      message.From.Username,
+	})
+	if reply != "" {
+		//nolint:errcheck // best effort reply
+		l.client.callAPI(context.Background(), "sendMessage", url.Values{
+			"chat_id":             {strconv.FormatInt(message.Chat.ID, 10)},
+			"text":                {reply},
+			"reply_to_message_id": {strconv.FormatInt(message.MessageID, 10)},
+		}, nil)
+	}
+}
+
+// dispatchCallback runs the callback handler and answers the query
+func (l *TelegramListener) dispatchCallback(query *TelegramCallbackQuery) {
+	l.mutex.RLock()
+	handler := l.callback
+	l.mutex.RUnlock()
+	if handler == nil {
+		return
+	}
+	values := url.Values{"callback_query_id": {query.ID}}
+	if text := handler(query); text != "" {
+		values.Set("text", text)
+	}
+	//nolint:errcheck // best effort answer
+	l.client.callAPI(context.Background(), "answerCallbackQuery", values, nil)
+}
+
+func (l *TelegramListener) getUpdates(ctx context.Context) ([]TelegramUpdate, error) {
+	var updates []TelegramUpdate
+	err := l.client.callAPI(ctx, "getUpdates", url.Values{
+		"offset":          {strconv.FormatInt(l.offset, 10)},
+		"timeout":         {strconv.Itoa(telegramPollTimeout)},
+		"allowed_updates": {`["message","callback_query"]`},
+	}, &updates)
+	return updates, err
+}
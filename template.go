@@ -0,0 +1,71 @@
+package notify
+
+import (
+	"os"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// TemplateData are the fields available to the message templates of the providers
+type TemplateData struct {
+	Severity  string
+	Timestamp time.Time
+	Hostname  string
+	// Summary is the first line of the data
+	Summary string
+	// Data is the message
+	Data string
+}
+
+// templateFuncs are available to the message templates
+var templateFuncs = template.FuncMap{
+	"json":       customWebhookFuncs["json"],
+	"markdownV2": EscapeMarkdownV2,
+	"upper":      strings.ToUpper,
+	"lower":      strings.ToLower,
+}
+
+// messageTemplates render the messages of the providers with a template
+type messageTemplates struct {
+	hostname  string
+	templates map[string]*template.Template
+}
+
+// parseTemplates parses the text/template of each provider
+func parseTemplates(texts map[string]string) (*messageTemplates, error) {
+	t := &messageTemplates{templates: make(map[string]*template.Template, len(texts))}
+	for provider, text := range texts {
+		tpl, err := template.New(provider).Funcs(templateFuncs).Parse(text)
+		if err != nil {
+			return nil, withProvider(provider, err)
+		}
+		t.templates[provider] = tpl
+	}
+	t.hostname, _ = os.Hostname()
+	return t, nil
+}
+
+// render returns the message rendered by the template of the provider, as is without one
+func (t *messageTemplates) render(provider, severity, message string) (string, error) {
+	if t == nil {
+		return message, nil
+	}
+	tpl, ok := t.templates[provider]
+	if !ok {
+		return message, nil
+	}
+	summary := message
+	if i := strings.IndexByte(summary, '\n'); i >= 0 {
+		summary = summary[:i]
+	}
+	var rendered strings.Builder
+	err := tpl.Execute(&rendered, &TemplateData{
+		Severity:  severity,
+		Timestamp: time.Now(),
+		Hostname:  t.hostname,
+		Summary:   summary,
+		Data:      message,
+	})
+	return rendered.String(), err
+}
@@ -0,0 +1,98 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// DefaultWebexTimeout to conclude operations
+const DefaultWebexTimeout = 5 * time.Second
+
+// WebexMessagesEndpoint of the messages api
+const WebexMessagesEndpoint = "https://webexapis.com/v1/messages"
+
+// ErrWebexNoRecipient is returned when neither a room nor a person are configured
+var ErrWebexNoRecipient = errors.New("webex room id or person email required")
+
+// WebexClient posts messages with a webex bot
+type WebexClient struct {
+	client *retryablehttp.Client
+	Token  string
+	// RoomID receives the messages, PersonEmail receives them directly otherwise
+	RoomID      string
+	PersonEmail string
+	// Markdown renders the messages as markdown
+	Markdown bool
+	TimeOut  time.Duration
+}
+
+// WebexMessage json structure
+type WebexMessage struct {
+	RoomID        string `json:"roomId,omitempty"`
+	ToPersonEmail string `json:"toPersonEmail,omitempty"`
+	Text          string `json:"text,omitempty"`
+	Markdown      string `json:"markdown,omitempty"`
+}
+
+// SendInfo to webex
+func (wc *WebexClient) SendInfo(message string) error {
+	return wc.SendInfoCtx(context.Background(), message)
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (wc *WebexClient) SendInfoCtx(ctx context.Context, message string) error {
+	webexMessage := &WebexMessage{RoomID: wc.RoomID}
+	if wc.RoomID == "" {
+		webexMessage.ToPersonEmail = wc.PersonEmail
+	}
+	if wc.Markdown {
+		webexMessage.Markdown = message
+	} else {
+		webexMessage.Text = message
+	}
+	return wc.SendWebexNotificationCtx(ctx, webexMessage)
+}
+
+// SendWebexNotification with json structure
+func (wc *WebexClient) SendWebexNotification(webexMessage *WebexMessage) error {
+	return wc.SendWebexNotificationCtx(context.Background(), webexMessage)
+}
+
+// SendWebexNotificationCtx is SendWebexNotification canceled with the context
+func (wc *WebexClient) SendWebexNotificationCtx(ctx context.Context, webexMessage *WebexMessage) error {
+	if webexMessage.RoomID == "" && webexMessage.ToPersonEmail == "" {
+		return ErrWebexNoRecipient
+	}
+	body, err := json.Marshal(webexMessage)
+	if err != nil {
+		return err
+	}
+	req, err := retryablehttp.NewRequest(http.MethodPost, WebexMessagesEndpoint, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Authorization", "Bearer "+wc.Token)
+
+	resp, err := doTimeout(wc.client, req.WithContext(ctx), wc.TimeOut)
+	if err != nil {
+		return err
+	}
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck // silent fail
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newResponseError(resp, buf)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"text/template"
+	"time"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// DefaultCustomWebhookTimeout to conclude operations
+const DefaultCustomWebhookTimeout = 5 * time.Second
+
+// DefaultCustomWebhookBody is the body template used when none is configured
+const DefaultCustomWebhookBody = `{"text":{{json .Message}}}`
+
+// CustomWebhookClient sends notifications to any http endpoint, the body is
+// a text/template rendered with CustomWebhookData
+type CustomWebhookClient struct {
+	client *retryablehttp.Client
+	URL    string
+	// Method is POST if empty
+	Method  string
+	Headers map[string]string
+	// BodyTemplate is DefaultCustomWebhookBody if empty, the json function
+	// quotes values inside json payloads
+	BodyTemplate string
+	TimeOut      time.Duration
+}
+
+// CustomWebhookData are the fields available to the body template
+type CustomWebhookData struct {
+	Message  string
+	Summary  string
+	Severity string
+	Time     time.Time
+}
+
+var customWebhookFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// SendInfo to the webhook
+func (cc *CustomWebhookClient) SendInfo(message string) error {
+	return cc.SendInfoCtx(context.Background(), message)
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (cc *CustomWebhookClient) SendInfoCtx(ctx context.Context, message string) error {
+	return cc.send(ctx, "info", message)
+}
+
+// SendWarning to the webhook
+func (cc *CustomWebhookClient) SendWarning(message string) error {
+	return cc.send(context.Background(), "warning", message)
+}
+
+// SendError to the webhook
+func (cc *CustomWebhookClient) SendError(message string) error {
+	return cc.send(context.Background(), "error", message)
+}
+
+func (cc *CustomWebhookClient) send(ctx context.Context, severity, message string) error {
+	body, err := cc.render(&CustomWebhookData{Message: message, Summary: issueSummary(message, 200), Severity: severity, Time: time.Now()})
+	if err != nil {
+		return err
+	}
+	return cc.SendCustomWebhookNotificationCtx(ctx, body)
+}
+
+func (cc *CustomWebhookClient) render(data *CustomWebhookData) ([]byte, error) {
+	text := cc.BodyTemplate
+	if text == "" {
+		text = DefaultCustomWebhookBody
+	}
+	tpl, err := template.New("body").Funcs(customWebhookFuncs).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	var body bytes.Buffer
+	if err := tpl.Execute(&body, data); err != nil {
+		return nil, err
+	}
+	return body.Bytes(), nil
+}
+
+// SendCustomWebhookNotification sends the rendered body with the configured method and headers
+func (cc *CustomWebhookClient) SendCustomWebhookNotification(body []byte) error {
+	return cc.SendCustomWebhookNotificationCtx(context.Background(), body)
+}
+
+// SendCustomWebhookNotificationCtx is SendCustomWebhookNotification canceled with the context
+func (cc *CustomWebhookClient) SendCustomWebhookNotificationCtx(ctx context.Context, body []byte) error {
+	method := cc.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+	req, err := retryablehttp.NewRequest(method, cc.URL, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range cc.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := doTimeout(cc.client, req.WithContext(ctx), cc.TimeOut)
+	if err != nil {
+		return err
+	}
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck // silent fail
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return newResponseError(resp, buf)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package notify
+
+import (
+	"errors"
+	"net/http"
+	"sync/atomic"
+)
+
+// WebhookStrategy distributes the messages of a provider over several webhook urls
+type WebhookStrategy string
+
+// Webhook strategies
+const (
+	// WebhookFailover uses the urls in order, the next one when a webhook
+	// is rate limited, revoked or unavailable. It's the default.
+	WebhookFailover WebhookStrategy = "failover"
+	// WebhookRoundRobin rotates the first url tried to spread rate limits
+	WebhookRoundRobin WebhookStrategy = "roundrobin"
+)
+
+// webhookPool rotates the webhook urls of a client
+type webhookPool struct {
+	next uint32
+}
+
+// webhookURLs returns the urls of the pool, the single url when none is set
+func webhookURLs(url string, urls []string) []string {
+	if len(urls) > 0 {
+		return urls
+	}
+	return []string{url}
+}
+
+// send delivers to the urls according to the strategy until one succeeds
+func (p *webhookPool) send(urls []string, strategy WebhookStrategy, send func(url string) error) error {
+	start := 0
+	if strategy == WebhookRoundRobin && len(urls) > 1 {
+		start = int(atomic.AddUint32(&p.next, 1) % uint32(len(urls)))
+	}
+	var err error
+	for i := range urls {
+		if err = send(urls[(start+i)%len(urls)]); err == nil || !shouldFailover(err) {
+			return err
+		}
+	}
+	return err
+}
+
+// shouldFailover reports errors of the webhook rather than the message
+func shouldFailover(err error) bool {
+	if IsRetryable(err) || errors.Is(err, ErrUnauthorized) {
+		return true
+	}
+	var providerErr *ProviderError
+	return errors.As(err, &providerErr) && (providerErr.StatusCode == http.StatusNotFound || providerErr.StatusCode == http.StatusGone)
+}
@@ -0,0 +1,117 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// DefaultWeComTimeout to conclude operations
+const DefaultWeComTimeout = 5 * time.Second
+
+// WeComMentionAll mentions every member of the group
+const WeComMentionAll = "@all"
+
+// WeComClient handling wecom group robot webhooks
+type WeComClient struct {
+	client     *retryablehttp.Client
+	WebHookURL string
+	// MentionedList are user ids, or WeComMentionAll, mentioned in text messages
+	MentionedList []string
+	// MentionedMobileList are phone numbers mentioned in text messages
+	MentionedMobileList []string
+	// Markdown sends the messages as markdown, mentions are written <@userid> in the content
+	Markdown bool
+	TimeOut  time.Duration
+}
+
+// WeComMessage json structure
+type WeComMessage struct {
+	MsgType  string         `json:"msgtype"`
+	Text     *WeComText     `json:"text,omitempty"`
+	Markdown *WeComMarkdown `json:"markdown,omitempty"`
+}
+
+// WeComText content
+type WeComText struct {
+	Content             string   `json:"content"`
+	MentionedList       []string `json:"mentioned_list,omitempty"`
+	MentionedMobileList []string `json:"mentioned_mobile_list,omitempty"`
+}
+
+// WeComMarkdown content
+type WeComMarkdown struct {
+	Content string `json:"content"`
+}
+
+// weComResponse of the robot api, errors are reported with status 200
+type weComResponse struct {
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+}
+
+// SendInfo to wecom
+func (wc *WeComClient) SendInfo(message string) error {
+	return wc.SendInfoCtx(context.Background(), message)
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (wc *WeComClient) SendInfoCtx(ctx context.Context, message string) error {
+	if wc.Markdown {
+		return wc.SendWeComNotificationCtx(ctx, &WeComMessage{MsgType: "markdown", Markdown: &WeComMarkdown{Content: message}})
+	}
+	return wc.SendWeComNotificationCtx(ctx, &WeComMessage{
+		MsgType: "text",
+		Text: &WeComText{
+			Content:             message,
+			MentionedList:       wc.MentionedList,
+			MentionedMobileList: wc.MentionedMobileList,
+		},
+	})
+}
+
+// SendWeComNotification with json structure
+func (wc *WeComClient) SendWeComNotification(weComMessage *WeComMessage) error {
+	return wc.SendWeComNotificationCtx(context.Background(), weComMessage)
+}
+
+// SendWeComNotificationCtx is SendWeComNotification canceled with the context
+func (wc *WeComClient) SendWeComNotificationCtx(ctx context.Context, weComMessage *WeComMessage) error {
+	body, err := json.Marshal(weComMessage)
+	if err != nil {
+		return err
+	}
+	req, err := retryablehttp.NewRequest(http.MethodPost, wc.WebHookURL, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/json")
+
+	resp, err := doTimeout(wc.client, req.WithContext(ctx), wc.TimeOut)
+	if err != nil {
+		return err
+	}
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck // silent fail
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newResponseError(resp, buf)
+	}
+	var response weComResponse
+	if err := json.Unmarshal(buf, &response); err != nil {
+		return err
+	}
+	if response.ErrCode != 0 {
+		return fmt.Errorf("wecom: %s (%d)", response.ErrMsg, response.ErrCode)
+	}
+	return nil
+}
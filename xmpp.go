@@ -0,0 +1,353 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// DefaultXMPPTimeout to conclude operations
+const DefaultXMPPTimeout = 15 * time.Second
+
+// xmpp namespaces
+const (
+	xmppNSTLS  = "urn:ietf:params:xml:ns:xmpp-tls"
+	xmppNSSASL = "urn:ietf:params:xml:ns:xmpp-sasl"
+	xmppNSBind = "urn:ietf:params:xml:ns:xmpp-bind"
+	xmppNSMUC  = "http://jabber.org/protocol/muc"
+)
+
+// ErrXMPPAuth is returned when the server rejects the credentials
+var ErrXMPPAuth = errors.New("xmpp authentication failed")
+
+// XMPPClient sends messages to a jid or a multi user chat room
+type XMPPClient struct {
+	// Server is the host:port to connect, the jid domain on port 5222 by default
+	Server string
+	// JID of the account as user@domain
+	JID      string
+	Password string
+	// To is the jid receiving the messages
+	To string
+	// Room is the jid of the muc room receiving the messages, joined as Nick
+	Room string
+	Nick string
+	// DirectTLS connects with implicit tls (port 5223) instead of STARTTLS
+	DirectTLS bool
+	TimeOut   time.Duration
+
+	capture captureFunc
+}
+
+// xmppFeatures announced by the server
+type xmppFeatures struct {
+	StartTLS   *struct{} `xml:"urn:ietf:params:xml:ns:xmpp-tls starttls"`
+	Mechanisms []string  `xml:"urn:ietf:params:xml:ns:xmpp-sasl mechanisms>mechanism"`
+	Bind       *struct{} `xml:"urn:ietf:params:xml:ns:xmpp-bind bind"`
+}
+
+// xmppSession is an open xmpp stream
+type xmppSession struct {
+	conn   net.Conn
+	dec    *xml.Decoder
+	domain string
+}
+
+// SendInfo to xmpp
+func (xc *XMPPClient) SendInfo(message string) error {
+	return xc.SendInfoCtx(context.Background(), message)
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (xc *XMPPClient) SendInfoCtx(ctx context.Context, message string) error {
+	return xc.SendXMPPNotificationCtx(ctx, message)
+}
+
+// SendXMPPNotification connects, delivers the message to the recipient
+// and the room, then closes the stream
+func (xc *XMPPClient) SendXMPPNotification(message string) error {
+	return xc.SendXMPPNotificationCtx(context.Background(), message)
+}
+
+// SendXMPPNotificationCtx is SendXMPPNotification canceled with the context
+func (xc *XMPPClient) SendXMPPNotificationCtx(ctx context.Context, message string) error {
+	if xc.capture != nil {
+		var stanzas []byte
+		for _, stanza := range []*xmppMessage{{To: xc.To, Type: "chat"}, {To: xc.Room, Type: "groupchat"}} {
+			if stanza.To == "" {
+				continue
+			}
+			stanza.Body = message
+			buf, err := xml.Marshal(stanza)
+			if err != nil {
+				return err
+			}
+			stanzas = append(stanzas, buf...)
+		}
+		xc.capture("xmpp:"+xc.JID, stanzas)
+		return nil
+	}
+
+	session, err := xc.connect(ctx)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck // silent fail
+	defer session.conn.Close()
+
+	if xc.To != "" {
+		if err := session.send(&xmppMessage{To: xc.To, Type: "chat", Body: message}); err != nil {
+			return err
+		}
+	}
+	if xc.Room != "" {
+		if err := session.joinRoom(xc.Room, xc.nick()); err != nil {
+			return err
+		}
+		if err := session.send(&xmppMessage{To: xc.Room, Type: "groupchat", Body: message}); err != nil {
+			return err
+		}
+	}
+	_, err = io.WriteString(session.conn, "</stream:stream>")
+	return err
+}
+
+func (xc *XMPPClient) nick() string {
+	if xc.Nick != "" {
+		return xc.Nick
+	}
+	return "notify"
+}
+
+// connect opens an authenticated and bound stream
+func (xc *XMPPClient) connect(ctx context.Context) (*xmppSession, error) {
+	parts := strings.SplitN(xc.JID, "@", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid jid %q", xc.JID)
+	}
+	user, domain := parts[0], strings.SplitN(parts[1], "/", 2)[0]
+
+	timeout := xc.TimeOut
+	if timeout == 0 {
+		timeout = DefaultXMPPTimeout
+	}
+	server := xc.Server
+	if server == "" {
+		port := "5222"
+		if xc.DirectTLS {
+			port = "5223"
+		}
+		server = net.JoinHostPort(domain, port)
+	}
+	host, _, err := net.SplitHostPort(server)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	tlsConfig := &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}
+
+	deadline := socketDeadline(ctx, timeout)
+	dialer := &net.Dialer{Timeout: timeout, Deadline: deadline}
+	var conn net.Conn
+	if xc.DirectTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", server, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", server)
+	}
+	if err != nil {
+		return nil, err
+	}
+	//nolint:errcheck // bounds the whole exchange
+	conn.SetDeadline(deadline)
+	session := &xmppSession{conn: conn, domain: domain}
+
+	features, err := session.open()
+	if err == nil && !xc.DirectTLS {
+		// plain text streams are never used to authenticate
+		if features.StartTLS == nil {
+			err = errors.New("xmpp server does not support STARTTLS")
+		} else {
+			err = session.startTLS(tlsConfig)
+		}
+		if err == nil {
+			features, err = session.open()
+		}
+	}
+	if err == nil {
+		err = session.authenticate(features, user, xc.Password)
+	}
+	if err == nil {
+		features, err = session.open()
+	}
+	if err == nil && features.Bind != nil {
+		err = session.bind()
+	}
+	if err != nil {
+		//nolint:errcheck // silent fail
+		conn.Close()
+		return nil, err
+	}
+	return session, nil
+}
+
+// open starts a new stream returning the server features
+func (s *xmppSession) open() (*xmppFeatures, error) {
+	s.dec = xml.NewDecoder(s.conn)
+	_, err := fmt.Fprintf(s.conn, "<?xml version='1.0'?><stream:stream to='%s' xmlns='jabber:client' "+
+		"xmlns:stream='http://etherx.jabber.org/streams' version='1.0'>", xmlEscape(s.domain))
+	if err != nil {
+		return nil, err
+	}
+	// the stream element stays open for the whole session
+	if _, err := s.next("stream"); err != nil {
+		return nil, err
+	}
+	start, err := s.next("features")
+	if err != nil {
+		return nil, err
+	}
+	var features xmppFeatures
+	return &features, s.dec.DecodeElement(&features, &start)
+}
+
+func (s *xmppSession) startTLS(config *tls.Config) error {
+	if _, err := fmt.Fprintf(s.conn, "<starttls xmlns='%s'/>", xmppNSTLS); err != nil {
+		return err
+	}
+	start, err := s.next("")
+	if err != nil {
+		return err
+	}
+	if start.Name.Local != "proceed" {
+		return errors.New("xmpp server refused STARTTLS")
+	}
+	conn := tls.Client(s.conn, config)
+	if err := conn.Handshake(); err != nil {
+		return err
+	}
+	s.conn = conn
+	return nil
+}
+
+func (s *xmppSession) authenticate(features *xmppFeatures, user, password string) error {
+	var plain bool
+	for _, mechanism := range features.Mechanisms {
+		plain = plain || mechanism == "PLAIN"
+	}
+	if !plain {
+		return errors.New("xmpp server does not support PLAIN authentication")
+	}
+	credentials := base64.StdEncoding.EncodeToString([]byte("\x00" + user + "\x00" + password))
+	if _, err := fmt.Fprintf(s.conn, "<auth xmlns='%s' mechanism='PLAIN'>%s</auth>", xmppNSSASL, credentials); err != nil {
+		return err
+	}
+	start, err := s.next("")
+	if err != nil {
+		return err
+	}
+	if start.Name.Local != "success" {
+		return ErrXMPPAuth
+	}
+	return s.dec.Skip()
+}
+
+func (s *xmppSession) bind() error {
+	_, err := fmt.Fprintf(s.conn, "<iq type='set' id='bind'><bind xmlns='%s'><resource>notify</resource></bind></iq>", xmppNSBind)
+	if err != nil {
+		return err
+	}
+	start, err := s.next("iq")
+	if err != nil {
+		return err
+	}
+	for _, attr := range start.Attr {
+		if attr.Name.Local == "type" && attr.Value == "error" {
+			return errors.New("xmpp resource binding failed")
+		}
+	}
+	return s.dec.Skip()
+}
+
+// joinRoom enters the room and waits for the presence of the own occupant
+func (s *xmppSession) joinRoom(room, nick string) error {
+	occupant := room + "/" + nick
+	_, err := fmt.Fprintf(s.conn, "<presence to='%s'><x xmlns='%s'><history maxstanzas='0'/></x></presence>", xmlEscape(occupant), xmppNSMUC)
+	if err != nil {
+		return err
+	}
+	for {
+		start, err := s.next("presence")
+		if err != nil {
+			return err
+		}
+		var presence struct {
+			From  string    `xml:"from,attr"`
+			Type  string    `xml:"type,attr"`
+			Error *struct{} `xml:"error"`
+		}
+		if err := s.dec.DecodeElement(&presence, &start); err != nil {
+			return err
+		}
+		if !strings.EqualFold(presence.From, occupant) {
+			continue
+		}
+		if presence.Type == "error" || presence.Error != nil {
+			return fmt.Errorf("could not join xmpp room %s", room)
+		}
+		return nil
+	}
+}
+
+// xmppMessage stanza
+type xmppMessage struct {
+	XMLName xml.Name `xml:"message"`
+	To      string   `xml:"to,attr"`
+	Type    string   `xml:"type,attr"`
+	Body    string   `xml:"body"`
+}
+
+func (s *xmppSession) send(stanza interface{}) error {
+	buf, err := xml.Marshal(stanza)
+	if err != nil {
+		return err
+	}
+	_, err = s.conn.Write(buf)
+	return err
+}
+
+// next returns the next start element, of the named element when name is not empty
+func (s *xmppSession) next(name string) (xml.StartElement, error) {
+	for {
+		token, err := s.dec.Token()
+		if err != nil {
+			return xml.StartElement{}, err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if name == "" || start.Name.Local == name {
+			return start, nil
+		}
+		if err := s.dec.Skip(); err != nil {
+			return xml.StartElement{}, err
+		}
+	}
+}
+
+func xmlEscape(s string) string {
+	var buf bytes.Buffer
+	//nolint:errcheck // writes to memory
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
@@ -0,0 +1,114 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+// DefaultZulipTimeout to conclude operations
+const DefaultZulipTimeout = 5 * time.Second
+
+// DefaultZulipTopic is used for stream messages without topic
+const DefaultZulipTopic = "notify"
+
+// ErrZulipNoRecipient is returned when neither a stream nor private recipients are configured
+var ErrZulipNoRecipient = errors.New("zulip stream or recipients required")
+
+// ZulipClient posts messages with a zulip bot
+type ZulipClient struct {
+	client *retryablehttp.Client
+	// URL of the organization as https://example.zulipchat.com
+	URL      string
+	BotEmail string
+	APIKey   string
+	// Stream and Topic receive the messages, private messages are sent to To otherwise
+	Stream  string
+	Topic   string
+	To      []string
+	TimeOut time.Duration
+}
+
+// SendInfo to zulip, the content is zulip markdown
+func (zc *ZulipClient) SendInfo(message string) error {
+	return zc.SendInfoCtx(context.Background(), message)
+}
+
+// SendInfoCtx is SendInfo canceled with the context
+func (zc *ZulipClient) SendInfoCtx(ctx context.Context, message string) error {
+	if zc.Stream != "" {
+		return zc.SendStreamCtx(ctx, zc.Stream, zc.Topic, message)
+	}
+	if len(zc.To) > 0 {
+		return zc.SendPrivateCtx(ctx, zc.To, message)
+	}
+	return ErrZulipNoRecipient
+}
+
+// SendStream posts the content to the topic of the stream
+func (zc *ZulipClient) SendStream(stream, topic, content string) error {
+	return zc.SendStreamCtx(context.Background(), stream, topic, content)
+}
+
+// SendStreamCtx is SendStream canceled with the context
+func (zc *ZulipClient) SendStreamCtx(ctx context.Context, stream, topic, content string) error {
+	if topic == "" {
+		topic = DefaultZulipTopic
+	}
+	return zc.sendMessage(ctx, url.Values{
+		"type":    {"stream"},
+		"to":      {stream},
+		"topic":   {topic},
+		"content": {content},
+	})
+}
+
+// SendPrivate sends the content as private message to the users emails
+func (zc *ZulipClient) SendPrivate(to []string, content string) error {
+	return zc.SendPrivateCtx(context.Background(), to, content)
+}
+
+// SendPrivateCtx is SendPrivate canceled with the context
+func (zc *ZulipClient) SendPrivateCtx(ctx context.Context, to []string, content string) error {
+	recipients, err := json.Marshal(to)
+	if err != nil {
+		return err
+	}
+	return zc.sendMessage(ctx, url.Values{
+		"type":    {"private"},
+		"to":      {string(recipients)},
+		"content": {content},
+	})
+}
+
+func (zc *ZulipClient) sendMessage(ctx context.Context, values url.Values) error {
+	req, err := retryablehttp.NewRequest(http.MethodPost, strings.TrimSuffix(zc.URL, "/")+"/api/v1/messages", []byte(values.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(zc.BotEmail, zc.APIKey)
+
+	resp, err := doTimeout(zc.client, req.WithContext(ctx), zc.TimeOut)
+	if err != nil {
+		return err
+	}
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck // silent fail
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newResponseError(resp, buf)
+	}
+	return nil
+}